@@ -2,10 +2,16 @@ module github.com/joaogabriel01/storage-orchestrator/example1
 
 go 1.21.6
 
+require (
+	github.com/go-redis/redis/v8 v8.11.5
+	github.com/joaogabriel01/storage-orchestrator v0.0.0-20261017131107-d19a2634d526
+	github.com/lib/pq v1.10.9
+)
+
 require (
 	github.com/cespare/xxhash/v2 v2.1.2 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
-	github.com/go-redis/redis/v8 v8.11.5 // indirect
-	github.com/joaogabriel01/storage-orchestrator v0.0.0-20240901201103-a4d2c067e515 // indirect
-	github.com/lib/pq v1.10.9 // indirect
 )
+
+// The example builds against the orchestrator in this repository.
+replace github.com/joaogabriel01/storage-orchestrator => ../..
@@ -48,10 +48,11 @@ func main() {
 	}
 	// user = `{"id": "1", "details": "details"}`
 
-	err = orchestrator.Delete("1")
+	deleted, err := orchestrator.Delete("1")
 	if err != nil {
 		panic(err)
 	}
+	// deleted = ["redis", "postgres"]
 
-	fmt.Println(saved, user)
+	fmt.Println(saved, user, deleted)
 }
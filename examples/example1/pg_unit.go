@@ -4,9 +4,11 @@ import (
 	"context"
 	"database/sql"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
 	_ "github.com/lib/pq"
 )
 
@@ -54,6 +56,9 @@ func (p *PostgresStorageUnit) Save(ctx context.Context, _, userJson string) erro
 func (p *PostgresStorageUnit) Get(ctx context.Context, key string) (string, error) {
 	var user User
 	err := p.db.QueryRowContext(ctx, "SELECT id, details FROM users WHERE id = $1", key).Scan(&user.ID, &user.Details)
+	if errors.Is(err, sql.ErrNoRows) {
+		return "", protocols.ErrNotFound
+	}
 	if err != nil {
 		return "", err
 	}
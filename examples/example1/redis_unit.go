@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
 )
 
 type RedisStorageUnit struct {
@@ -24,7 +25,7 @@ func (r *RedisStorageUnit) Save(ctx context.Context, key string, value string) e
 func (r *RedisStorageUnit) Get(ctx context.Context, key string) (string, error) {
 	val, err := r.client.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return "", nil
+		return "", protocols.ErrNotFound
 	}
 	return val, err
 }
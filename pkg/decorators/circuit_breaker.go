@@ -0,0 +1,313 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+type CircuitState int
+
+const (
+	CircuitClosed CircuitState = iota
+	CircuitOpen
+	CircuitHalfOpen
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "unknown"
+}
+
+// CircuitBreakerConfig configures a CircuitBreakerUnit. The circuit opens after
+// FailureThreshold consecutive failures (5 when zero) and stays open for
+// CoolDown (30s when zero). It then lets one call at a time through and closes
+// again after HalfOpenSuccesses of them succeed (1 when zero), or opens again
+// on the first failure. IsFailure decides which errors count as failures; by
+// default clean misses do not. A call whose context was canceled counts as
+// neither a failure nor a success.
+type CircuitBreakerConfig struct {
+	FailureThreshold  int
+	CoolDown          time.Duration
+	HalfOpenSuccesses int
+	IsFailure         func(error) bool
+}
+
+type CircuitBreakerUnit[K any, V any] struct {
+	unit   protocols.StorageUnit[K, V]
+	config CircuitBreakerConfig
+
+	mu        sync.Mutex
+	state     CircuitState
+	failures  int
+	successes int
+	openedAt  time.Time
+	probing   bool
+}
+
+type CircuitBreakerBatchUnit[K any, V any] struct {
+	*CircuitBreakerUnit[K, V]
+	batch protocols.BatchStorageUnit[K, V]
+}
+
+// CircuitBreaker is the unit returned by NewCircuitBreakerUnit, either a
+// *CircuitBreakerUnit or a *CircuitBreakerBatchUnit.
+type CircuitBreaker[K any, V any] interface {
+	protocols.StorageUnit[K, V]
+	protocols.AvailabilityReporter
+	State() CircuitState
+}
+
+// NewCircuitBreakerUnit guards every call made to unit with a circuit breaker.
+// Units that implement protocols.BatchStorageUnit keep their batch methods.
+func NewCircuitBreakerUnit[K any, V any](unit protocols.StorageUnit[K, V], config CircuitBreakerConfig) CircuitBreaker[K, V] {
+	if config.FailureThreshold <= 0 {
+		config.FailureThreshold = 5
+	}
+	if config.CoolDown <= 0 {
+		config.CoolDown = 30 * time.Second
+	}
+	if config.HalfOpenSuccesses <= 0 {
+		config.HalfOpenSuccesses = 1
+	}
+	if config.IsFailure == nil {
+		config.IsFailure = func(err error) bool {
+			return !errors.Is(err, protocols.ErrNotFound)
+		}
+	}
+	breaker := &CircuitBreakerUnit[K, V]{unit: unit, config: config}
+	if batch, ok := unit.(protocols.BatchStorageUnit[K, V]); ok {
+		return &CircuitBreakerBatchUnit[K, V]{CircuitBreakerUnit: breaker, batch: batch}
+	}
+	return breaker
+}
+
+func (c *CircuitBreakerUnit[K, V]) State() CircuitState {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.coolDown()
+	return c.state
+}
+
+// Available reports whether a call would be let through right now.
+func (c *CircuitBreakerUnit[K, V]) Available() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.coolDown()
+	return c.state == CircuitClosed || (c.state == CircuitHalfOpen && !c.probing)
+}
+
+func (c *CircuitBreakerUnit[K, V]) Unwrap() protocols.StorageUnit[K, V] {
+	return c.unit
+}
+
+func (c *CircuitBreakerUnit[K, V]) Save(ctx context.Context, query K, item V) error {
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err := c.unit.Save(ctx, query, item)
+	c.record(err)
+	return err
+}
+
+func (c *CircuitBreakerUnit[K, V]) Get(ctx context.Context, query K) (V, error) {
+	if err := c.allow(); err != nil {
+		var value V
+		return value, err
+	}
+	value, err := c.unit.Get(ctx, query)
+	c.record(err)
+	return value, err
+}
+
+// GetWithMetadata does not count a unit without metadata support as failing.
+func (c *CircuitBreakerUnit[K, V]) GetWithMetadata(ctx context.Context, query K) (V, protocols.Metadata, error) {
+	metadataUnit, ok := c.unit.(protocols.MetadataStorageUnit[K, V])
+	if !ok {
+		var value V
+		return value, protocols.Metadata{}, protocols.ErrMetadataUnsupported
+	}
+	if err := c.allow(); err != nil {
+		var value V
+		return value, protocols.Metadata{}, err
+	}
+	value, metadata, err := metadataUnit.GetWithMetadata(ctx, query)
+	c.record(err)
+	return value, metadata, err
+}
+
+func (c *CircuitBreakerUnit[K, V]) Delete(ctx context.Context, query K) error {
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err := c.unit.Delete(ctx, query)
+	c.record(err)
+	return err
+}
+
+// Prepare, Commit and Abort do not count a unit without transaction support
+// as failing.
+func (c *CircuitBreakerUnit[K, V]) Prepare(ctx context.Context, tx string, query K, item V) error {
+	unit, err := transactional(c.unit)
+	if err != nil {
+		return err
+	}
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err = unit.Prepare(ctx, tx, query, item)
+	c.record(err)
+	return err
+}
+
+func (c *CircuitBreakerUnit[K, V]) Commit(ctx context.Context, tx string) error {
+	unit, err := transactional(c.unit)
+	if err != nil {
+		return err
+	}
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err = unit.Commit(ctx, tx)
+	c.record(err)
+	return err
+}
+
+func (c *CircuitBreakerUnit[K, V]) Abort(ctx context.Context, tx string) error {
+	unit, err := transactional(c.unit)
+	if err != nil {
+		return err
+	}
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err = unit.Abort(ctx, tx)
+	c.record(err)
+	return err
+}
+
+func (c *CircuitBreakerBatchUnit[K, V]) SaveMany(ctx context.Context, queries []K, items []V) error {
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err := c.batch.SaveMany(ctx, queries, items)
+	c.record(err)
+	return err
+}
+
+// GetMany counts the call as failing when any key failed, and as canceled when
+// no key failed but some were canceled.
+func (c *CircuitBreakerBatchUnit[K, V]) GetMany(ctx context.Context, queries []K) ([]V, []error) {
+	if err := c.allow(); err != nil {
+		errs := make([]error, len(queries))
+		for index := range errs {
+			errs[index] = err
+		}
+		return make([]V, len(queries)), errs
+	}
+	values, errs := c.batch.GetMany(ctx, queries)
+	var failure error
+	for _, err := range errs {
+		if errors.Is(err, context.Canceled) {
+			failure = err
+		} else if err != nil && c.config.IsFailure(err) {
+			failure = err
+			break
+		}
+	}
+	c.record(failure)
+	return values, errs
+}
+
+func (c *CircuitBreakerBatchUnit[K, V]) DeleteMany(ctx context.Context, queries []K) error {
+	if err := c.allow(); err != nil {
+		return err
+	}
+	err := c.batch.DeleteMany(ctx, queries)
+	c.record(err)
+	return err
+}
+
+// coolDown moves an open circuit to half-open once the cool-down elapsed. The
+// caller must hold c.mu.
+func (c *CircuitBreakerUnit[K, V]) coolDown() {
+	if c.state == CircuitOpen && time.Since(c.openedAt) >= c.config.CoolDown {
+		c.state = CircuitHalfOpen
+		c.successes = 0
+		c.probing = false
+	}
+}
+
+func (c *CircuitBreakerUnit[K, V]) allow() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.coolDown()
+
+	switch c.state {
+	case CircuitOpen:
+		return protocols.ErrCircuitOpen
+	case CircuitHalfOpen:
+		if c.probing {
+			return protocols.ErrCircuitOpen
+		}
+		c.probing = true
+	}
+	return nil
+}
+
+// record counts the outcome of a call. A canceled call tells nothing about the
+// unit, so it only frees the half-open probe.
+func (c *CircuitBreakerUnit[K, V]) record(err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if errors.Is(err, context.Canceled) {
+		c.probing = false
+		return
+	}
+	failed := err != nil && c.config.IsFailure(err)
+
+	switch c.state {
+	case CircuitClosed:
+		if !failed {
+			c.failures = 0
+			return
+		}
+		c.failures++
+		if c.failures >= c.config.FailureThreshold {
+			c.open()
+		}
+	case CircuitHalfOpen:
+		c.probing = false
+		if failed {
+			c.open()
+			return
+		}
+		c.successes++
+		if c.successes >= c.config.HalfOpenSuccesses {
+			c.state = CircuitClosed
+			c.failures = 0
+		}
+	}
+}
+
+func (c *CircuitBreakerUnit[K, V]) open() {
+	c.state = CircuitOpen
+	c.openedAt = time.Now()
+	c.failures = 0
+}
+
+var _ protocols.MetadataStorageUnit[any, any] = (*CircuitBreakerUnit[any, any])(nil)
+var _ protocols.TransactionalStorageUnit[any, any] = (*CircuitBreakerUnit[any, any])(nil)
+var _ protocols.AvailabilityReporter = (*CircuitBreakerUnit[any, any])(nil)
+var _ protocols.BatchStorageUnit[any, any] = (*CircuitBreakerBatchUnit[any, any])(nil)
+var _ CircuitBreaker[any, any] = (*CircuitBreakerBatchUnit[any, any])(nil)
@@ -0,0 +1,202 @@
+package decorators
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var breaker CircuitBreaker[string, string]
+
+func circuitBreakerSetup() {
+	unitMock = unit_test.NewUnitMock()
+	breaker = NewCircuitBreakerUnit[string, string](unitMock, CircuitBreakerConfig{
+		FailureThreshold: 2,
+		CoolDown:         20 * time.Millisecond,
+	})
+}
+
+func TestCircuitBreakerUnit(t *testing.T) {
+
+	t.Run("should open after consecutive failures and fail fast", func(t *testing.T) {
+		circuitBreakerSetup()
+		ctx := context.Background()
+		unitMock.On("Get", "query", mock.Anything).Return("", fmt.Errorf("down"))
+
+		_, err := breaker.Get(ctx, "query")
+		assert.EqualError(t, err, "down")
+		assert.Equal(t, CircuitClosed, breaker.State())
+
+		_, err = breaker.Get(ctx, "query")
+		assert.EqualError(t, err, "down")
+		assert.Equal(t, CircuitOpen, breaker.State())
+		assert.False(t, breaker.Available())
+
+		_, err = breaker.Get(ctx, "query")
+		assert.ErrorIs(t, err, protocols.ErrCircuitOpen)
+		unitMock.AssertNumberOfCalls(t, "Get", 2)
+	})
+
+	t.Run("should not count clean misses or reset on success", func(t *testing.T) {
+		circuitBreakerSetup()
+		ctx := context.Background()
+		unitMock.On("Get", "miss", mock.Anything).Return("", protocols.ErrNotFound)
+		unitMock.On("Save", "query", "value", mock.Anything).Return(fmt.Errorf("down")).Once()
+		unitMock.On("Save", "query", "value", mock.Anything).Return(nil).Once()
+		unitMock.On("Delete", "query", mock.Anything).Return(fmt.Errorf("down")).Once()
+
+		breaker.Get(ctx, "miss")
+		breaker.Get(ctx, "miss")
+		breaker.Save(ctx, "query", "value")
+		breaker.Save(ctx, "query", "value")
+		breaker.Delete(ctx, "query")
+
+		assert.Equal(t, CircuitClosed, breaker.State())
+	})
+
+	t.Run("should let one probe through after the cool-down and close when it succeeds", func(t *testing.T) {
+		circuitBreakerSetup()
+		ctx := context.Background()
+		unitMock.On("Delete", "query", mock.Anything).Return(fmt.Errorf("down")).Twice()
+		unitMock.On("Delete", "query", mock.Anything).Return(nil)
+
+		breaker.Delete(ctx, "query")
+		breaker.Delete(ctx, "query")
+		assert.Equal(t, CircuitOpen, breaker.State())
+
+		time.Sleep(30 * time.Millisecond)
+		assert.Equal(t, CircuitHalfOpen, breaker.State())
+		assert.True(t, breaker.Available())
+
+		assert.NoError(t, breaker.Delete(ctx, "query"))
+		assert.Equal(t, CircuitClosed, breaker.State())
+	})
+
+	t.Run("should open again when the probe fails", func(t *testing.T) {
+		circuitBreakerSetup()
+		ctx := context.Background()
+		unitMock.On("Save", "query", "value", mock.Anything).Return(fmt.Errorf("down"))
+
+		breaker.Save(ctx, "query", "value")
+		breaker.Save(ctx, "query", "value")
+		time.Sleep(30 * time.Millisecond)
+
+		assert.EqualError(t, breaker.Save(ctx, "query", "value"), "down")
+		assert.Equal(t, CircuitOpen, breaker.State())
+		unitMock.AssertNumberOfCalls(t, "Save", 3)
+	})
+
+	t.Run("should reject other calls while the probe is running", func(t *testing.T) {
+		circuitBreakerSetup()
+		ctx := context.Background()
+		probing := make(chan struct{})
+		release := make(chan struct{})
+		unitMock.On("Get", "query", mock.Anything).Return("", fmt.Errorf("down")).Twice()
+		unitMock.On("Get", "query", mock.Anything).Run(func(mock.Arguments) {
+			close(probing)
+			<-release
+		}).Return("value", nil).Once()
+
+		breaker.Get(ctx, "query")
+		breaker.Get(ctx, "query")
+		time.Sleep(30 * time.Millisecond)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			breaker.Get(ctx, "query")
+		}()
+		<-probing
+
+		_, err := breaker.Get(ctx, "query")
+		assert.ErrorIs(t, err, protocols.ErrCircuitOpen)
+		assert.False(t, breaker.Available())
+
+		close(release)
+		<-done
+		assert.Equal(t, CircuitClosed, breaker.State())
+	})
+
+	t.Run("should neither close nor open on a canceled probe", func(t *testing.T) {
+		circuitBreakerSetup()
+		ctx := context.Background()
+		unitMock.On("Get", "query", mock.Anything).Return("", fmt.Errorf("down")).Twice()
+		unitMock.On("Get", "query", mock.Anything).Return("", context.Canceled).Once()
+		unitMock.On("Get", "query", mock.Anything).Return("value", nil).Once()
+
+		breaker.Get(ctx, "query")
+		breaker.Get(ctx, "query")
+		time.Sleep(30 * time.Millisecond)
+
+		_, err := breaker.Get(ctx, "query")
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, CircuitHalfOpen, breaker.State())
+		assert.True(t, breaker.Available())
+
+		_, err = breaker.Get(ctx, "query")
+		assert.NoError(t, err)
+		assert.Equal(t, CircuitClosed, breaker.State())
+	})
+
+	t.Run("should not reset the failures on a canceled call", func(t *testing.T) {
+		circuitBreakerSetup()
+		ctx := context.Background()
+		unitMock.On("Delete", "query", mock.Anything).Return(fmt.Errorf("down")).Once()
+		unitMock.On("Delete", "query", mock.Anything).Return(context.Canceled).Once()
+		unitMock.On("Delete", "query", mock.Anything).Return(fmt.Errorf("down")).Once()
+
+		breaker.Delete(ctx, "query")
+		breaker.Delete(ctx, "query")
+		breaker.Delete(ctx, "query")
+
+		assert.Equal(t, CircuitOpen, breaker.State())
+	})
+}
+
+var batchBreaker CircuitBreaker[string, string]
+var batchUnitMock *unit_test.BatchUnitMock
+
+func circuitBreakerBatchSetup() {
+	batchUnitMock = unit_test.NewBatchUnitMock()
+	batchBreaker = NewCircuitBreakerUnit[string, string](batchUnitMock, CircuitBreakerConfig{
+		FailureThreshold: 1,
+		CoolDown:         time.Minute,
+	})
+}
+
+func TestCircuitBreakerBatchUnit(t *testing.T) {
+
+	t.Run("should keep the batch methods of the unit", func(t *testing.T) {
+		circuitBreakerBatchSetup()
+		ctx := context.Background()
+		batchUnitMock.On("GetMany", []string{"a", "b"}, mock.Anything).Return([]string{"va", ""}, []error{nil, protocols.ErrNotFound})
+
+		batch, ok := batchBreaker.(protocols.BatchStorageUnit[string, string])
+		assert.True(t, ok)
+		values, errs := batch.GetMany(ctx, []string{"a", "b"})
+		assert.Equal(t, []string{"va", ""}, values)
+		assert.ErrorIs(t, errs[1], protocols.ErrNotFound)
+		assert.Equal(t, CircuitClosed, batchBreaker.State())
+	})
+
+	t.Run("should open on a failing batch and fail every key fast", func(t *testing.T) {
+		circuitBreakerBatchSetup()
+		ctx := context.Background()
+		batchUnitMock.On("SaveMany", []string{"a"}, []string{"va"}, mock.Anything).Return(fmt.Errorf("down")).Once()
+
+		batch := batchBreaker.(protocols.BatchStorageUnit[string, string])
+		assert.EqualError(t, batch.SaveMany(ctx, []string{"a"}, []string{"va"}), "down")
+		assert.Equal(t, CircuitOpen, batchBreaker.State())
+
+		_, errs := batch.GetMany(ctx, []string{"a", "b"})
+		assert.ErrorIs(t, errs[0], protocols.ErrCircuitOpen)
+		assert.ErrorIs(t, errs[1], protocols.ErrCircuitOpen)
+		batchUnitMock.AssertNotCalled(t, "GetMany", mock.Anything, mock.Anything)
+	})
+}
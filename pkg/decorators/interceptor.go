@@ -0,0 +1,172 @@
+package decorators
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+type InterceptedUnit[K any, V any] struct {
+	Unit protocols.StorageUnit[K, V]
+	Name string
+	call protocols.UnitCallFunc[K, V]
+}
+
+type InterceptedBatchUnit[K any, V any] struct {
+	InterceptedUnit[K, V]
+}
+
+// NewInterceptedUnit runs every call made to unit through interceptors, the
+// first one being the outermost. Units that implement
+// protocols.BatchStorageUnit keep their batch methods.
+func NewInterceptedUnit[K any, V any](name string, unit protocols.StorageUnit[K, V], interceptors ...protocols.Interceptor[K, V]) protocols.StorageUnit[K, V] {
+	intercepted := InterceptedUnit[K, V]{Unit: unit, Name: name}
+	intercepted.call = intercepted.invoke
+	for c := len(interceptors) - 1; c >= 0; c-- {
+		intercepted.call = interceptors[c](intercepted.call)
+	}
+
+	if _, ok := unit.(protocols.BatchStorageUnit[K, V]); ok {
+		return &InterceptedBatchUnit[K, V]{InterceptedUnit: intercepted}
+	}
+	return &intercepted
+}
+
+func (i *InterceptedUnit[K, V]) invoke(ctx context.Context, call *protocols.UnitCall[K, V]) error {
+	defer func() {
+		call.Duration = time.Since(call.Start)
+	}()
+
+	switch call.Operation {
+	case protocols.SaveOperation:
+		return i.Unit.Save(ctx, call.Query, call.Item)
+	case protocols.GetOperation:
+		value, err := i.Unit.Get(ctx, call.Query)
+		call.Value = value
+		return err
+	case protocols.GetWithMetadataOperation:
+		value, metadata, err := getWithMetadata(ctx, i.Unit, call.Query)
+		call.Value, call.Metadata = value, metadata
+		return err
+	case protocols.DeleteOperation:
+		return i.Unit.Delete(ctx, call.Query)
+	case protocols.PrepareOperation, protocols.CommitOperation, protocols.AbortOperation:
+		return i.invokeTransaction(ctx, call)
+	}
+
+	batch, ok := i.Unit.(protocols.BatchStorageUnit[K, V])
+	if !ok {
+		return fmt.Errorf("unit %v does not support %v", i.Name, call.Operation)
+	}
+
+	switch call.Operation {
+	case protocols.SaveManyOperation:
+		return batch.SaveMany(ctx, call.Queries, call.Items)
+	case protocols.GetManyOperation:
+		call.Values, call.Errors = batch.GetMany(ctx, call.Queries)
+		return protocols.BatchErrors(call.Errors).ErrorOrNil()
+	case protocols.DeleteManyOperation:
+		return batch.DeleteMany(ctx, call.Queries)
+	}
+	return fmt.Errorf("unknown unit operation %v", call.Operation)
+}
+
+func (i *InterceptedUnit[K, V]) invokeTransaction(ctx context.Context, call *protocols.UnitCall[K, V]) error {
+	unit, err := transactional(i.Unit)
+	if err != nil {
+		return err
+	}
+
+	switch call.Operation {
+	case protocols.PrepareOperation:
+		return unit.Prepare(ctx, call.Transaction, call.Query, call.Item)
+	case protocols.CommitOperation:
+		return unit.Commit(ctx, call.Transaction)
+	}
+	return unit.Abort(ctx, call.Transaction)
+}
+
+func (i *InterceptedUnit[K, V]) newCall(operation protocols.UnitOperation) *protocols.UnitCall[K, V] {
+	return &protocols.UnitCall[K, V]{Operation: operation, Unit: i.Name, Start: time.Now()}
+}
+
+func (i *InterceptedUnit[K, V]) Save(ctx context.Context, query K, item V) error {
+	call := i.newCall(protocols.SaveOperation)
+	call.Query, call.Item = query, item
+	return i.call(ctx, call)
+}
+
+func (i *InterceptedUnit[K, V]) Get(ctx context.Context, query K) (V, error) {
+	call := i.newCall(protocols.GetOperation)
+	call.Query = query
+	err := i.call(ctx, call)
+	return call.Value, err
+}
+
+func (i *InterceptedUnit[K, V]) GetWithMetadata(ctx context.Context, query K) (V, protocols.Metadata, error) {
+	call := i.newCall(protocols.GetWithMetadataOperation)
+	call.Query = query
+	err := i.call(ctx, call)
+	return call.Value, call.Metadata, err
+}
+
+func (i *InterceptedUnit[K, V]) Delete(ctx context.Context, query K) error {
+	call := i.newCall(protocols.DeleteOperation)
+	call.Query = query
+	return i.call(ctx, call)
+}
+
+func (i *InterceptedUnit[K, V]) Prepare(ctx context.Context, tx string, query K, item V) error {
+	call := i.newCall(protocols.PrepareOperation)
+	call.Transaction, call.Query, call.Item = tx, query, item
+	return i.call(ctx, call)
+}
+
+func (i *InterceptedUnit[K, V]) Commit(ctx context.Context, tx string) error {
+	call := i.newCall(protocols.CommitOperation)
+	call.Transaction = tx
+	return i.call(ctx, call)
+}
+
+func (i *InterceptedUnit[K, V]) Abort(ctx context.Context, tx string) error {
+	call := i.newCall(protocols.AbortOperation)
+	call.Transaction = tx
+	return i.call(ctx, call)
+}
+
+func (i *InterceptedUnit[K, V]) Unwrap() protocols.StorageUnit[K, V] {
+	return i.Unit
+}
+
+func (i *InterceptedBatchUnit[K, V]) SaveMany(ctx context.Context, queries []K, items []V) error {
+	call := i.newCall(protocols.SaveManyOperation)
+	call.Queries, call.Items = queries, items
+	return i.call(ctx, call)
+}
+
+func (i *InterceptedBatchUnit[K, V]) GetMany(ctx context.Context, queries []K) ([]V, []error) {
+	call := i.newCall(protocols.GetManyOperation)
+	call.Queries = queries
+	err := i.call(ctx, call)
+
+	if len(call.Values) != len(queries) || len(call.Errors) != len(queries) {
+		call.Values = make([]V, len(queries))
+		call.Errors = make([]error, len(queries))
+		for index := range call.Errors {
+			call.Errors[index] = err
+		}
+	}
+	return call.Values, call.Errors
+}
+
+func (i *InterceptedBatchUnit[K, V]) DeleteMany(ctx context.Context, queries []K) error {
+	call := i.newCall(protocols.DeleteManyOperation)
+	call.Queries = queries
+	return i.call(ctx, call)
+}
+
+var _ protocols.MetadataStorageUnit[any, any] = (*InterceptedUnit[any, any])(nil)
+var _ protocols.TransactionalStorageUnit[any, any] = (*InterceptedUnit[any, any])(nil)
+var _ protocols.BatchStorageUnit[any, any] = (*InterceptedBatchUnit[any, any])(nil)
@@ -0,0 +1,81 @@
+package decorators
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func recordCalls(calls *[]string, label string) protocols.Interceptor[string, string] {
+	return func(next protocols.UnitCallFunc[string, string]) protocols.UnitCallFunc[string, string] {
+		return func(ctx context.Context, call *protocols.UnitCall[string, string]) error {
+			*calls = append(*calls, fmt.Sprintf("%v %v %v %v", label, call.Operation, call.Unit, call.Query))
+			return next(ctx, call)
+		}
+	}
+}
+
+func TestInterceptedUnit(t *testing.T) {
+
+	t.Run("should run the interceptors in order around the unit", func(t *testing.T) {
+		unitMock = unit_test.NewUnitMock()
+		ctx := context.Background()
+		unitMock.On("Get", "query", mock.Anything).Return("value", nil)
+
+		var calls []string
+		var outcome *protocols.UnitCall[string, string]
+		observe := func(next protocols.UnitCallFunc[string, string]) protocols.UnitCallFunc[string, string] {
+			return func(ctx context.Context, call *protocols.UnitCall[string, string]) error {
+				err := next(ctx, call)
+				outcome = call
+				return err
+			}
+		}
+
+		unit := NewInterceptedUnit[string, string]("mock", unitMock, recordCalls(&calls, "first"), recordCalls(&calls, "second"), observe)
+		value, err := unit.Get(ctx, "query")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+		assert.Equal(t, []string{"first get mock query", "second get mock query"}, calls)
+		assert.Equal(t, "value", outcome.Value)
+		assert.False(t, outcome.Start.IsZero())
+		assert.Positive(t, outcome.Duration)
+	})
+
+	t.Run("should let an interceptor fail the call without reaching the unit", func(t *testing.T) {
+		unitMock = unit_test.NewUnitMock()
+		reject := func(next protocols.UnitCallFunc[string, string]) protocols.UnitCallFunc[string, string] {
+			return func(ctx context.Context, call *protocols.UnitCall[string, string]) error {
+				return fmt.Errorf("rejected")
+			}
+		}
+
+		unit := NewInterceptedUnit[string, string]("mock", unitMock, reject)
+
+		assert.EqualError(t, unit.Save(context.Background(), "query", "value"), "rejected")
+		unitMock.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should keep the batch methods of the unit", func(t *testing.T) {
+		batchMock := unit_test.NewBatchUnitMock()
+		ctx := context.Background()
+		batchMock.On("GetMany", []string{"a", "b"}, mock.Anything).Return([]string{"1", ""}, []error{nil, protocols.ErrNotFound})
+
+		var calls []string
+		unit := NewInterceptedUnit[string, string]("batch", batchMock, recordCalls(&calls, "seen"))
+		batch, ok := unit.(protocols.BatchStorageUnit[string, string])
+		assert.True(t, ok)
+
+		values, errs := batch.GetMany(ctx, []string{"a", "b"})
+		assert.Equal(t, []string{"1", ""}, values)
+		assert.NoError(t, errs[0])
+		assert.ErrorIs(t, errs[1], protocols.ErrNotFound)
+		assert.Equal(t, []string{"seen get-many batch "}, calls)
+	})
+}
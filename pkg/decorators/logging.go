@@ -0,0 +1,45 @@
+package decorators
+
+import (
+	"context"
+	"log/slog"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// LoggingInterceptor logs every unit call: failures at warn level and the
+// rest, clean misses included, at debug level. Keys are logged through
+// redactKey.
+func LoggingInterceptor[K any, V any](logger *slog.Logger, strategy string, redactKey protocols.KeyRedactor[K]) protocols.Interceptor[K, V] {
+	return func(next protocols.UnitCallFunc[K, V]) protocols.UnitCallFunc[K, V] {
+		return func(ctx context.Context, call *protocols.UnitCall[K, V]) error {
+			err := next(ctx, call)
+
+			level := slog.LevelDebug
+			if protocols.Outcome(err) == protocols.OutcomeError {
+				level = slog.LevelWarn
+			}
+			if !logger.Enabled(ctx, level) {
+				return err
+			}
+
+			attributes := []slog.Attr{
+				slog.String("operation", string(call.Operation)),
+				slog.String("unit", call.Unit),
+				slog.String("strategy", strategy),
+			}
+			if call.Queries != nil {
+				attributes = append(attributes, slog.Int("keys", len(call.Queries)))
+			} else {
+				attributes = append(attributes, slog.String("key", redactKey(call.Query)))
+			}
+			attributes = append(attributes, slog.Duration("duration", call.Duration))
+			if err != nil {
+				attributes = append(attributes, slog.Any("error", err))
+			}
+
+			logger.LogAttrs(ctx, level, "unit call", attributes...)
+			return err
+		}
+	}
+}
@@ -0,0 +1,18 @@
+package decorators
+
+import (
+	"context"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// getWithMetadata calls GetWithMetadata on unit, or returns
+// protocols.ErrMetadataUnsupported when the unit does not implement it.
+func getWithMetadata[K any, V any](ctx context.Context, unit protocols.StorageUnit[K, V], query K) (V, protocols.Metadata, error) {
+	metadataUnit, ok := unit.(protocols.MetadataStorageUnit[K, V])
+	if !ok {
+		var value V
+		return value, protocols.Metadata{}, protocols.ErrMetadataUnsupported
+	}
+	return metadataUnit.GetWithMetadata(ctx, query)
+}
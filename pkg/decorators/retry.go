@@ -0,0 +1,198 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// Retry calls fn until it succeeds, returns an error the policy does not
+// retry, or runs out of attempts. It stops waiting as soon as ctx is done.
+func Retry(ctx context.Context, policy protocols.RetryPolicy, fn func() error) error {
+	attempts := policy.MaxAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+
+	for attempt := 1; ; attempt++ {
+		err := fn()
+		if err == nil || attempt >= attempts || !policy.IsRetryable(err) {
+			return err
+		}
+
+		timer := time.NewTimer(backoff(policy, attempt))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return fmt.Errorf("retry stopped after %v attempts: %w", attempt, errors.Join(err, ctx.Err()))
+		case <-timer.C:
+		}
+	}
+}
+
+func backoff(policy protocols.RetryPolicy, attempt int) time.Duration {
+	multiplier := policy.Multiplier
+	if multiplier <= 0 {
+		multiplier = 2
+	}
+
+	wait := float64(policy.InitialBackoff) * math.Pow(multiplier, float64(attempt-1))
+	if policy.MaxBackoff > 0 && wait > float64(policy.MaxBackoff) {
+		wait = float64(policy.MaxBackoff)
+	}
+	if policy.Jitter > 0 {
+		wait += wait * policy.Jitter * (2*rand.Float64() - 1)
+	}
+	if wait < 0 {
+		return 0
+	}
+	return time.Duration(wait)
+}
+
+type RetryUnit[K any, V any] struct {
+	Unit   protocols.StorageUnit[K, V]
+	Policy protocols.RetryPolicy
+}
+
+type RetryBatchUnit[K any, V any] struct {
+	RetryUnit[K, V]
+	batch protocols.BatchStorageUnit[K, V]
+}
+
+// NewRetryUnit wraps unit so that every call is retried according to policy.
+// Units that implement protocols.BatchStorageUnit keep their batch methods.
+func NewRetryUnit[K any, V any](unit protocols.StorageUnit[K, V], policy protocols.RetryPolicy) protocols.StorageUnit[K, V] {
+	retryUnit := RetryUnit[K, V]{Unit: unit, Policy: policy}
+	if batch, ok := unit.(protocols.BatchStorageUnit[K, V]); ok {
+		return &RetryBatchUnit[K, V]{RetryUnit: retryUnit, batch: batch}
+	}
+	return &retryUnit
+}
+
+func (r *RetryUnit[K, V]) Save(ctx context.Context, query K, item V) error {
+	return Retry(ctx, r.Policy, func() error {
+		return r.Unit.Save(ctx, query, item)
+	})
+}
+
+func (r *RetryUnit[K, V]) Get(ctx context.Context, query K) (V, error) {
+	var value V
+	err := Retry(ctx, r.Policy, func() error {
+		var err error
+		value, err = r.Unit.Get(ctx, query)
+		return err
+	})
+	return value, err
+}
+
+func (r *RetryUnit[K, V]) GetWithMetadata(ctx context.Context, query K) (V, protocols.Metadata, error) {
+	var value V
+	var metadata protocols.Metadata
+	err := Retry(ctx, r.Policy, func() error {
+		var err error
+		value, metadata, err = getWithMetadata(ctx, r.Unit, query)
+		return err
+	})
+	return value, metadata, err
+}
+
+func (r *RetryUnit[K, V]) Delete(ctx context.Context, query K) error {
+	return Retry(ctx, r.Policy, func() error {
+		return r.Unit.Delete(ctx, query)
+	})
+}
+
+func (r *RetryUnit[K, V]) Prepare(ctx context.Context, tx string, query K, item V) error {
+	unit, err := transactional(r.Unit)
+	if err != nil {
+		return err
+	}
+	return Retry(ctx, r.Policy, func() error {
+		return unit.Prepare(ctx, tx, query, item)
+	})
+}
+
+func (r *RetryUnit[K, V]) Commit(ctx context.Context, tx string) error {
+	unit, err := transactional(r.Unit)
+	if err != nil {
+		return err
+	}
+	return Retry(ctx, r.Policy, func() error {
+		return unit.Commit(ctx, tx)
+	})
+}
+
+func (r *RetryUnit[K, V]) Abort(ctx context.Context, tx string) error {
+	unit, err := transactional(r.Unit)
+	if err != nil {
+		return err
+	}
+	return Retry(ctx, r.Policy, func() error {
+		return unit.Abort(ctx, tx)
+	})
+}
+
+func (r *RetryUnit[K, V]) Unwrap() protocols.StorageUnit[K, V] {
+	return r.Unit
+}
+
+func (r *RetryBatchUnit[K, V]) SaveMany(ctx context.Context, queries []K, items []V) error {
+	return Retry(ctx, r.Policy, func() error {
+		return r.batch.SaveMany(ctx, queries, items)
+	})
+}
+
+// GetMany retries only the keys whose error the policy retries.
+func (r *RetryBatchUnit[K, V]) GetMany(ctx context.Context, queries []K) ([]V, []error) {
+	values := make([]V, len(queries))
+	errs := make([]error, len(queries))
+	pending := make([]int, len(queries))
+	for index := range pending {
+		pending[index] = index
+	}
+
+	Retry(ctx, r.Policy, func() error {
+		batch := make([]K, len(pending))
+		for position, index := range pending {
+			batch[position] = queries[index]
+		}
+
+		found, foundErrs := r.batch.GetMany(ctx, batch)
+		if len(found) != len(batch) || len(foundErrs) != len(batch) {
+			err := fmt.Errorf("unit returned %v values and %v errors for %v keys", len(found), len(foundErrs), len(batch))
+			for _, index := range pending {
+				errs[index] = err
+			}
+			return err
+		}
+
+		var retry []int
+		var retryErr error
+		for position, index := range pending {
+			values[index], errs[index] = found[position], foundErrs[position]
+			if errs[index] != nil && r.Policy.IsRetryable(errs[index]) {
+				retry = append(retry, index)
+				retryErr = errs[index]
+			}
+		}
+		pending = retry
+		return retryErr
+	})
+
+	return values, errs
+}
+
+func (r *RetryBatchUnit[K, V]) DeleteMany(ctx context.Context, queries []K) error {
+	return Retry(ctx, r.Policy, func() error {
+		return r.batch.DeleteMany(ctx, queries)
+	})
+}
+
+var _ protocols.MetadataStorageUnit[any, any] = (*RetryUnit[any, any])(nil)
+var _ protocols.TransactionalStorageUnit[any, any] = (*RetryUnit[any, any])(nil)
+var _ protocols.BatchStorageUnit[any, any] = (*RetryBatchUnit[any, any])(nil)
@@ -0,0 +1,134 @@
+package decorators
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var unitMock *unit_test.UnitMock
+var policy protocols.RetryPolicy
+
+func retrySetup() {
+	unitMock = unit_test.NewUnitMock()
+	policy = protocols.RetryPolicy{
+		MaxAttempts:    3,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     5 * time.Millisecond,
+		Jitter:         0.5,
+	}
+}
+
+func TestRetryUnit(t *testing.T) {
+
+	t.Run("should retry until the unit succeeds", func(t *testing.T) {
+		retrySetup()
+		ctx := context.Background()
+		unitMock.On("Save", "query", "value", mock.Anything).Return(fmt.Errorf("transient")).Twice()
+		unitMock.On("Save", "query", "value", mock.Anything).Return(nil).Once()
+
+		err := NewRetryUnit[string, string](unitMock, policy).Save(ctx, "query", "value")
+
+		assert.NoError(t, err)
+		unitMock.AssertNumberOfCalls(t, "Save", 3)
+	})
+
+	t.Run("should return the last error after the maximum attempts", func(t *testing.T) {
+		retrySetup()
+		ctx := context.Background()
+		unitMock.On("Delete", "query", mock.Anything).Return(fmt.Errorf("down"))
+
+		err := NewRetryUnit[string, string](unitMock, policy).Delete(ctx, "query")
+
+		assert.EqualError(t, err, "down")
+		unitMock.AssertNumberOfCalls(t, "Delete", 3)
+	})
+
+	t.Run("should not retry a clean miss", func(t *testing.T) {
+		retrySetup()
+		ctx := context.Background()
+		unitMock.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+
+		_, err := NewRetryUnit[string, string](unitMock, policy).Get(ctx, "query")
+
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+		unitMock.AssertNumberOfCalls(t, "Get", 1)
+	})
+
+	t.Run("should use the retryable classifier", func(t *testing.T) {
+		retrySetup()
+		ctx := context.Background()
+		permanent := fmt.Errorf("permanent")
+		policy.Retryable = func(err error) bool {
+			return err != permanent
+		}
+		unitMock.On("Get", "query", mock.Anything).Return("", permanent)
+
+		_, err := NewRetryUnit[string, string](unitMock, policy).Get(ctx, "query")
+
+		assert.ErrorIs(t, err, permanent)
+		unitMock.AssertNumberOfCalls(t, "Get", 1)
+	})
+
+	t.Run("should stop waiting when the context is canceled", func(t *testing.T) {
+		retrySetup()
+		ctx, cancel := context.WithCancel(context.Background())
+		policy.InitialBackoff = time.Hour
+		unitMock.On("Save", "query", "value", mock.Anything).Run(func(mock.Arguments) {
+			cancel()
+		}).Return(fmt.Errorf("transient"))
+
+		err := NewRetryUnit[string, string](unitMock, policy).Save(ctx, "query", "value")
+
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorContains(t, err, "transient")
+		unitMock.AssertNumberOfCalls(t, "Save", 1)
+	})
+
+	t.Run("should keep the batch methods and retry only the failed keys", func(t *testing.T) {
+		retrySetup()
+		ctx := context.Background()
+		batchMock := unit_test.NewBatchUnitMock()
+		batchMock.On("GetMany", []string{"a", "b", "c"}, mock.Anything).Return([]string{"va", "", ""}, []error{nil, fmt.Errorf("transient"), protocols.ErrNotFound}).Once()
+		batchMock.On("GetMany", []string{"b"}, mock.Anything).Return([]string{"vb"}, []error{nil}).Once()
+
+		unit, ok := NewRetryUnit[string, string](batchMock, policy).(protocols.BatchStorageUnit[string, string])
+		assert.True(t, ok)
+
+		values, errs := unit.GetMany(ctx, []string{"a", "b", "c"})
+
+		assert.Equal(t, []string{"va", "vb", ""}, values)
+		assert.NoError(t, errs[0])
+		assert.NoError(t, errs[1])
+		assert.ErrorIs(t, errs[2], protocols.ErrNotFound)
+		batchMock.AssertExpectations(t)
+	})
+}
+
+func TestBackoff(t *testing.T) {
+
+	t.Run("should grow exponentially up to the maximum backoff", func(t *testing.T) {
+		policy := protocols.RetryPolicy{InitialBackoff: 10 * time.Millisecond, MaxBackoff: 50 * time.Millisecond}
+
+		assert.Equal(t, 10*time.Millisecond, backoff(policy, 1))
+		assert.Equal(t, 20*time.Millisecond, backoff(policy, 2))
+		assert.Equal(t, 40*time.Millisecond, backoff(policy, 3))
+		assert.Equal(t, 50*time.Millisecond, backoff(policy, 4))
+	})
+
+	t.Run("should stay within the jitter", func(t *testing.T) {
+		policy := protocols.RetryPolicy{InitialBackoff: 100 * time.Millisecond, Jitter: 0.2}
+
+		for c := 0; c < 100; c++ {
+			wait := backoff(policy, 1)
+			assert.GreaterOrEqual(t, wait, 80*time.Millisecond)
+			assert.LessOrEqual(t, wait, 120*time.Millisecond)
+		}
+	})
+}
@@ -0,0 +1,144 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+type TimeoutUnit[K any, V any] struct {
+	Unit    protocols.StorageUnit[K, V]
+	Name    string
+	Timeout time.Duration
+}
+
+type TimeoutBatchUnit[K any, V any] struct {
+	TimeoutUnit[K, V]
+	batch protocols.BatchStorageUnit[K, V]
+}
+
+// NewTimeoutUnit bounds every call made to unit to timeout, reporting calls that
+// run out of time as a *protocols.UnitTimeoutError naming the unit. Units that
+// implement protocols.BatchStorageUnit keep their batch methods.
+func NewTimeoutUnit[K any, V any](name string, unit protocols.StorageUnit[K, V], timeout time.Duration) protocols.StorageUnit[K, V] {
+	timeoutUnit := TimeoutUnit[K, V]{Unit: unit, Name: name, Timeout: timeout}
+	if batch, ok := unit.(protocols.BatchStorageUnit[K, V]); ok {
+		return &TimeoutBatchUnit[K, V]{TimeoutUnit: timeoutUnit, batch: batch}
+	}
+	return &timeoutUnit
+}
+
+func (t *TimeoutUnit[K, V]) call(ctx context.Context, fn func(ctx context.Context) error) error {
+	callCtx, cancel := context.WithTimeout(ctx, t.Timeout)
+	defer cancel()
+
+	err := fn(callCtx)
+	if err != nil && ctx.Err() == nil && errors.Is(callCtx.Err(), context.DeadlineExceeded) {
+		return &protocols.UnitTimeoutError{Unit: t.Name, Timeout: t.Timeout, Err: err}
+	}
+	return err
+}
+
+func (t *TimeoutUnit[K, V]) Save(ctx context.Context, query K, item V) error {
+	return t.call(ctx, func(ctx context.Context) error {
+		return t.Unit.Save(ctx, query, item)
+	})
+}
+
+func (t *TimeoutUnit[K, V]) Get(ctx context.Context, query K) (V, error) {
+	var value V
+	err := t.call(ctx, func(ctx context.Context) error {
+		var err error
+		value, err = t.Unit.Get(ctx, query)
+		return err
+	})
+	return value, err
+}
+
+func (t *TimeoutUnit[K, V]) GetWithMetadata(ctx context.Context, query K) (V, protocols.Metadata, error) {
+	var value V
+	var metadata protocols.Metadata
+	err := t.call(ctx, func(ctx context.Context) error {
+		var err error
+		value, metadata, err = getWithMetadata(ctx, t.Unit, query)
+		return err
+	})
+	return value, metadata, err
+}
+
+func (t *TimeoutUnit[K, V]) Delete(ctx context.Context, query K) error {
+	return t.call(ctx, func(ctx context.Context) error {
+		return t.Unit.Delete(ctx, query)
+	})
+}
+
+func (t *TimeoutUnit[K, V]) Prepare(ctx context.Context, tx string, query K, item V) error {
+	unit, err := transactional(t.Unit)
+	if err != nil {
+		return err
+	}
+	return t.call(ctx, func(ctx context.Context) error {
+		return unit.Prepare(ctx, tx, query, item)
+	})
+}
+
+func (t *TimeoutUnit[K, V]) Commit(ctx context.Context, tx string) error {
+	unit, err := transactional(t.Unit)
+	if err != nil {
+		return err
+	}
+	return t.call(ctx, func(ctx context.Context) error {
+		return unit.Commit(ctx, tx)
+	})
+}
+
+func (t *TimeoutUnit[K, V]) Abort(ctx context.Context, tx string) error {
+	unit, err := transactional(t.Unit)
+	if err != nil {
+		return err
+	}
+	return t.call(ctx, func(ctx context.Context) error {
+		return unit.Abort(ctx, tx)
+	})
+}
+
+func (t *TimeoutUnit[K, V]) Unwrap() protocols.StorageUnit[K, V] {
+	return t.Unit
+}
+
+func (t *TimeoutBatchUnit[K, V]) SaveMany(ctx context.Context, queries []K, items []V) error {
+	return t.call(ctx, func(ctx context.Context) error {
+		return t.batch.SaveMany(ctx, queries, items)
+	})
+}
+
+func (t *TimeoutBatchUnit[K, V]) GetMany(ctx context.Context, queries []K) ([]V, []error) {
+	var values []V
+	var errs []error
+	err := t.call(ctx, func(ctx context.Context) error {
+		values, errs = t.batch.GetMany(ctx, queries)
+		return errors.Join(errs...)
+	})
+
+	var timeout *protocols.UnitTimeoutError
+	if errors.As(err, &timeout) {
+		for index := range errs {
+			if errs[index] != nil {
+				errs[index] = &protocols.UnitTimeoutError{Unit: t.Name, Timeout: t.Timeout, Err: errs[index]}
+			}
+		}
+	}
+	return values, errs
+}
+
+func (t *TimeoutBatchUnit[K, V]) DeleteMany(ctx context.Context, queries []K) error {
+	return t.call(ctx, func(ctx context.Context) error {
+		return t.batch.DeleteMany(ctx, queries)
+	})
+}
+
+var _ protocols.MetadataStorageUnit[any, any] = (*TimeoutUnit[any, any])(nil)
+var _ protocols.TransactionalStorageUnit[any, any] = (*TimeoutUnit[any, any])(nil)
+var _ protocols.BatchStorageUnit[any, any] = (*TimeoutBatchUnit[any, any])(nil)
@@ -0,0 +1,71 @@
+package decorators
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func waitForContext(args mock.Arguments) {
+	<-args.Get(len(args) - 1).(context.Context).Done()
+}
+
+func TestTimeoutUnit(t *testing.T) {
+
+	t.Run("should report the unit that ran out of time", func(t *testing.T) {
+		unitMock = unit_test.NewUnitMock()
+		ctx := context.Background()
+		unitMock.On("Get", "query", mock.Anything).Run(waitForContext).Return("", context.DeadlineExceeded)
+
+		_, err := NewTimeoutUnit[string, string]("slow", unitMock, 5*time.Millisecond).Get(ctx, "query")
+
+		var timeout *protocols.UnitTimeoutError
+		assert.ErrorAs(t, err, &timeout)
+		assert.Equal(t, "slow", timeout.Unit)
+		assert.Equal(t, 5*time.Millisecond, timeout.Timeout)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("should not blame the unit when the operation ran out of time", func(t *testing.T) {
+		unitMock = unit_test.NewUnitMock()
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Millisecond)
+		defer cancel()
+		unitMock.On("Save", "query", "value", mock.Anything).Run(waitForContext).Return(context.DeadlineExceeded)
+
+		err := NewTimeoutUnit[string, string]("slow", unitMock, time.Hour).Save(ctx, "query", "value")
+
+		var timeout *protocols.UnitTimeoutError
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.False(t, errors.As(err, &timeout))
+	})
+
+	t.Run("should let a fast call through untouched", func(t *testing.T) {
+		unitMock = unit_test.NewUnitMock()
+		ctx := context.Background()
+		unitMock.On("Delete", "query", mock.Anything).Return(nil)
+
+		err := NewTimeoutUnit[string, string]("fast", unitMock, time.Second).Delete(ctx, "query")
+
+		assert.NoError(t, err)
+	})
+
+	t.Run("should be retried per attempt", func(t *testing.T) {
+		unitMock = unit_test.NewUnitMock()
+		ctx := context.Background()
+		unitMock.On("Get", "query", mock.Anything).Run(waitForContext).Return("", context.DeadlineExceeded).Once()
+		unitMock.On("Get", "query", mock.Anything).Return("value", nil).Once()
+
+		unit := NewRetryUnit(NewTimeoutUnit[string, string]("slow", unitMock, 5*time.Millisecond), protocols.RetryPolicy{MaxAttempts: 2})
+		value, err := unit.Get(ctx, "query")
+
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+		unitMock.AssertExpectations(t)
+	})
+}
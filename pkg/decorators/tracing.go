@@ -0,0 +1,36 @@
+package decorators
+
+import (
+	"context"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// TracingInterceptor starts a span around every unit call, as a child of the
+// span carried by the context of the call, with the unit, the strategy and
+// the outcome as attributes. Clean misses are not recorded as errors.
+func TracingInterceptor[K any, V any](tracer protocols.Tracer, strategy string) protocols.Interceptor[K, V] {
+	return func(next protocols.UnitCallFunc[K, V]) protocols.UnitCallFunc[K, V] {
+		return func(ctx context.Context, call *protocols.UnitCall[K, V]) error {
+			attributes := []protocols.Attribute{
+				{Key: protocols.AttributeOperation, Value: string(call.Operation)},
+				{Key: protocols.AttributeUnit, Value: call.Unit},
+				{Key: protocols.AttributeStrategy, Value: strategy},
+			}
+			if call.Queries != nil {
+				attributes = append(attributes, protocols.Attribute{Key: protocols.AttributeKeys, Value: len(call.Queries)})
+			}
+
+			ctx, span := tracer.Start(ctx, "unit."+string(call.Operation), attributes...)
+			defer span.End()
+
+			err := next(ctx, call)
+			outcome := protocols.Outcome(err)
+			span.SetAttributes(protocols.Attribute{Key: protocols.AttributeOutcome, Value: outcome})
+			if outcome == protocols.OutcomeError {
+				span.RecordError(err)
+			}
+			return err
+		}
+	}
+}
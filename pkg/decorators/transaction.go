@@ -0,0 +1,15 @@
+package decorators
+
+import (
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// transactional returns unit as a protocols.TransactionalStorageUnit, or
+// protocols.ErrTransactionsUnsupported when it does not implement it.
+func transactional[K any, V any](unit protocols.StorageUnit[K, V]) (protocols.TransactionalStorageUnit[K, V], error) {
+	transactionalUnit, ok := unit.(protocols.TransactionalStorageUnit[K, V])
+	if !ok {
+		return nil, protocols.ErrTransactionsUnsupported
+	}
+	return transactionalUnit, nil
+}
@@ -6,33 +6,40 @@ import (
 	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
 )
 
-func (o *Orchestrator[K, V]) defaultSaveOptions() protocols.SaveOptions {
+func (o *Orchestrator[K, V]) defaultSaveOptions(standardOrder []string) protocols.SaveOptions {
 	ctx := context.Background()
 
 	options := protocols.SaveOptions{
 		Context:       ctx,
 		HowWillItSave: protocols.Sequential,
-		Targets:       o.standardOrder,
+		Targets:       standardOrder,
 	}
 	return options
 }
 
-func (o *Orchestrator[K, V]) defaultGetOptions() protocols.GetOptions {
+func (o *Orchestrator[K, V]) defaultGetOptions(standardOrder []string) protocols.GetOptions {
 	ctx := context.Background()
 	options := protocols.GetOptions{
 		Context:      ctx,
 		HowWillItGet: protocols.Cache,
-		Targets:      o.standardOrder,
+		Targets:      standardOrder,
 	}
 	return options
 }
 
-func (o *Orchestrator[K, V]) defaultDeleteOptions() protocols.DeleteOptions {
+func (o *Orchestrator[K, V]) defaultDeleteOptions(standardOrder []string) protocols.DeleteOptions {
 	ctx := context.Background()
 	options := protocols.DeleteOptions{
 		Context:         ctx,
-		Targets:         o.standardOrder,
+		Targets:         standardOrder,
 		HowWillItDelete: protocols.SequentialDelete,
 	}
 	return options
 }
+
+func (o *Orchestrator[K, V]) defaultRemoveOptions() protocols.RemoveOptions {
+	options := protocols.RemoveOptions{
+		HowWillItRemove: protocols.DropFromStandardOrder,
+	}
+	return options
+}
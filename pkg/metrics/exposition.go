@@ -0,0 +1,117 @@
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// DefaultBuckets are the upper bounds, in seconds, of the latency histograms.
+var DefaultBuckets = []float64{.001, .0025, .005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10}
+
+type series struct {
+	labels  []string
+	value   float64
+	buckets []uint64
+	sum     float64
+	count   uint64
+}
+
+// family holds every series of a metric, keyed by their label values.
+type family struct {
+	name    string
+	help    string
+	kind    string
+	labels  []string
+	buckets []float64
+	series  map[string]*series
+}
+
+func newFamily(name, help, kind string, buckets []float64, labels ...string) *family {
+	return &family{name: name, help: help, kind: kind, labels: labels, buckets: buckets, series: map[string]*series{}}
+}
+
+func (f *family) with(values ...string) *series {
+	key := strings.Join(values, "\xff")
+	s, ok := f.series[key]
+	if !ok {
+		s = &series{labels: values}
+		if f.kind == "histogram" {
+			s.buckets = make([]uint64, len(f.buckets))
+		}
+		f.series[key] = s
+	}
+	return s
+}
+
+func (f *family) add(delta float64, values ...string) {
+	f.with(values...).value += delta
+}
+
+func (f *family) observe(value float64, values ...string) {
+	s := f.with(values...)
+	for c, bound := range f.buckets {
+		if value <= bound {
+			s.buckets[c]++
+		}
+	}
+	s.sum += value
+	s.count++
+}
+
+// write writes the family in the Prometheus text exposition format, with the
+// series sorted by their label values so scrapes are stable.
+func (f *family) write(w io.Writer) {
+	if len(f.series) == 0 {
+		return
+	}
+	fmt.Fprintf(w, "# HELP %v %v\n", f.name, f.help)
+	fmt.Fprintf(w, "# TYPE %v %v\n", f.name, f.kind)
+
+	keys := make([]string, 0, len(f.series))
+	for key := range f.series {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		s := f.series[key]
+		if f.kind != "histogram" {
+			fmt.Fprintf(w, "%v%v %v\n", f.name, formatLabels(f.labels, s.labels), formatValue(s.value))
+			continue
+		}
+
+		names := append(append([]string(nil), f.labels...), "le")
+		for c, bound := range f.buckets {
+			values := append(append([]string(nil), s.labels...), formatValue(bound))
+			fmt.Fprintf(w, "%v_bucket%v %v\n", f.name, formatLabels(names, values), s.buckets[c])
+		}
+		values := append(append([]string(nil), s.labels...), "+Inf")
+		fmt.Fprintf(w, "%v_bucket%v %v\n", f.name, formatLabels(names, values), s.count)
+		fmt.Fprintf(w, "%v_sum%v %v\n", f.name, formatLabels(f.labels, s.labels), formatValue(s.sum))
+		fmt.Fprintf(w, "%v_count%v %v\n", f.name, formatLabels(f.labels, s.labels), s.count)
+	}
+}
+
+var labelEscaper = strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`)
+
+func formatLabels(names, values []string) string {
+	if len(names) == 0 {
+		return ""
+	}
+	pairs := make([]string, len(names))
+	for c, name := range names {
+		pairs[c] = fmt.Sprintf(`%v="%v"`, name, labelEscaper.Replace(values[c]))
+	}
+	return "{" + strings.Join(pairs, ",") + "}"
+}
+
+func formatValue(value float64) string {
+	if math.IsInf(value, 1) {
+		return "+Inf"
+	}
+	return strconv.FormatFloat(value, 'g', -1, 64)
+}
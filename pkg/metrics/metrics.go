@@ -0,0 +1,212 @@
+// Package metrics collects the measurements of an orchestrator and serves them
+// in the Prometheus text exposition format.
+package metrics
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+const namespace = "storage_orchestrator"
+
+type Registry struct {
+	mu sync.Mutex
+
+	operations         *family
+	operationErrors    *family
+	operationDurations *family
+	hits               *family
+	misses             *family
+	backfills          *family
+
+	unitCalls     *family
+	unitErrors    *family
+	unitHits      *family
+	unitMisses    *family
+	unitDurations *family
+}
+
+func NewRegistry() *Registry {
+	return &Registry{
+		operations:         newFamily(namespace+"_operations_total", "Operations run, by operation and strategy.", "counter", nil, "operation", "strategy"),
+		operationErrors:    newFamily(namespace+"_operation_errors_total", "Operations that failed, by error class. Clean misses are counted as misses.", "counter", nil, "operation", "strategy", "class"),
+		operationDurations: newFamily(namespace+"_operation_duration_seconds", "Duration of the operations.", "histogram", DefaultBuckets, "operation", "strategy"),
+		hits:               newFamily(namespace+"_get_hits_total", "Keys found by get operations, by the unit and the position in the targets (tier) that returned them.", "counter", nil, "strategy", "unit", "tier"),
+		misses:             newFamily(namespace+"_get_misses_total", "Keys that no unit returned.", "counter", nil, "strategy"),
+		backfills:          newFamily(namespace+"_backfills_total", "Keys saved back into a unit by get operations.", "counter", nil, "strategy", "unit"),
+
+		unitCalls:     newFamily(namespace+"_unit_calls_total", "Calls made to the units.", "counter", nil, "unit", "operation"),
+		unitErrors:    newFamily(namespace+"_unit_errors_total", "Unit calls that failed, by error class. Clean misses are counted as misses.", "counter", nil, "unit", "operation", "class"),
+		unitHits:      newFamily(namespace+"_unit_hits_total", "Keys a unit returned.", "counter", nil, "unit"),
+		unitMisses:    newFamily(namespace+"_unit_misses_total", "Keys a unit did not have.", "counter", nil, "unit"),
+		unitDurations: newFamily(namespace+"_unit_call_duration_seconds", "Duration of the unit calls.", "histogram", DefaultBuckets, "unit", "operation"),
+	}
+}
+
+// Instrument records the operations of orchestrator and the calls it makes to
+// its units into registry.
+func Instrument[K any, V any](orchestrator protocols.StorageOrchestrator[K, V], registry *Registry) {
+	orchestrator.Use(Interceptor[K, V](registry))
+	orchestrator.Observe(registry.ObserveOperation)
+}
+
+// ObserveOperation records an operation. It is a protocols.OperationObserver.
+func (r *Registry) ObserveOperation(info protocols.OperationInfo) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	operation := string(info.Operation)
+	r.operations.add(1, operation, info.Strategy)
+	r.operationDurations.observe(info.Duration.Seconds(), operation, info.Strategy)
+	if class := ErrorClass(info.Err); class != "" && !missClass(class) {
+		r.operationErrors.add(1, operation, info.Strategy, class)
+	}
+
+	if info.Get == nil {
+		return
+	}
+	for _, hit := range info.Get.Hits() {
+		r.hits.add(1, info.Strategy, hit.Unit, strconv.Itoa(hit.Tier))
+	}
+	for unit, keys := range info.Get.Backfills() {
+		r.backfills.add(float64(keys), info.Strategy, unit)
+	}
+	if misses := countMisses(info.Err); misses > 0 {
+		r.misses.add(float64(misses), info.Strategy)
+	}
+}
+
+// countMisses returns how many keys err reports as missing.
+func countMisses(err error) int {
+	var batch protocols.BatchErrors
+	if errors.As(err, &batch) {
+		misses := 0
+		for _, err := range batch {
+			misses += countMisses(err)
+		}
+		return misses
+	}
+	if missClass(ErrorClass(err)) {
+		return 1
+	}
+	return 0
+}
+
+// missClass reports whether an error class is a miss rather than a failure.
+func missClass(class string) bool {
+	return class == "not_found" || class == "unconfirmed_miss"
+}
+
+// Interceptor returns an interceptor that records every unit call into
+// registry.
+func Interceptor[K any, V any](registry *Registry) protocols.Interceptor[K, V] {
+	return func(next protocols.UnitCallFunc[K, V]) protocols.UnitCallFunc[K, V] {
+		return func(ctx context.Context, call *protocols.UnitCall[K, V]) error {
+			err := next(ctx, call)
+
+			var keyErrs []error
+			switch call.Operation {
+			case protocols.GetOperation, protocols.GetWithMetadataOperation:
+				keyErrs = []error{err}
+			case protocols.GetManyOperation:
+				keyErrs = call.Errors
+				if len(keyErrs) != len(call.Queries) {
+					keyErrs = make([]error, len(call.Queries))
+					for index := range keyErrs {
+						keyErrs[index] = err
+					}
+				}
+			}
+			registry.observeUnitCall(call.Unit, string(call.Operation), call.Duration.Seconds(), err, keyErrs)
+			return err
+		}
+	}
+}
+
+// observeUnitCall records a unit call; keyErrs holds the outcome of each key
+// read by get calls.
+func (r *Registry) observeUnitCall(unit, operation string, seconds float64, err error, keyErrs []error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.unitCalls.add(1, unit, operation)
+	r.unitDurations.observe(seconds, unit, operation)
+	if class := ErrorClass(err); class != "" && class != "not_found" {
+		r.unitErrors.add(1, unit, operation, class)
+	}
+
+	for _, err := range keyErrs {
+		switch {
+		case err == nil:
+			r.unitHits.add(1, unit)
+		case errors.Is(err, protocols.ErrNotFound):
+			r.unitMisses.add(1, unit)
+		}
+	}
+}
+
+// ErrorClass sorts err into a small set of classes fit for a metric label:
+// rollback_failed, quorum_not_reached, circuit_open, timeout, canceled,
+// no_targets, unknown_strategy, batch_unsupported, unit_not_found, not_found,
+// unconfirmed_miss, read_only_unit, transactions_unsupported or other. It returns an empty
+// string for a nil error. Causes are checked in that order, so an error that
+// wraps a timeout and a miss is a timeout.
+func ErrorClass(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, protocols.ErrRollbackFailed):
+		return "rollback_failed"
+	case errors.Is(err, protocols.ErrQuorumNotReached):
+		return "quorum_not_reached"
+	case errors.Is(err, protocols.ErrCircuitOpen):
+		return "circuit_open"
+	case errors.Is(err, context.DeadlineExceeded):
+		return "timeout"
+	case errors.Is(err, context.Canceled):
+		return "canceled"
+	case errors.Is(err, protocols.ErrNoTargets):
+		return "no_targets"
+	case errors.Is(err, protocols.ErrUnknownStrategy):
+		return "unknown_strategy"
+	case errors.Is(err, protocols.ErrBatchUnsupported):
+		return "batch_unsupported"
+	case errors.Is(err, protocols.ErrUnitNotFound):
+		return "unit_not_found"
+	case errors.Is(err, protocols.ErrNotFound):
+		return "not_found"
+	case errors.Is(err, protocols.ErrUnconfirmedMiss):
+		return "unconfirmed_miss"
+	case errors.Is(err, protocols.ErrReadOnlyUnit):
+		return "read_only_unit"
+	case errors.Is(err, protocols.ErrTransactionsUnsupported):
+		return "transactions_unsupported"
+	}
+	return "other"
+}
+
+// ServeHTTP serves every metric in the Prometheus text exposition format. The
+// metrics are rendered before writing the response, so a slow scrape never
+// holds up the calls being recorded.
+func (r *Registry) ServeHTTP(w http.ResponseWriter, _ *http.Request) {
+	var body bytes.Buffer
+	r.mu.Lock()
+	for _, f := range []*family{
+		r.operations, r.operationErrors, r.operationDurations, r.hits, r.misses, r.backfills,
+		r.unitCalls, r.unitErrors, r.unitHits, r.unitMisses, r.unitDurations,
+	} {
+		f.write(&body)
+	}
+	r.mu.Unlock()
+
+	w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+	body.WriteTo(w)
+}
+
+var _ http.Handler = (*Registry)(nil)
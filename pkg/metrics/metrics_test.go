@@ -0,0 +1,138 @@
+package metrics
+
+import (
+	"context"
+	"fmt"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg"
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func scrape(registry *Registry) string {
+	recorder := httptest.NewRecorder()
+	registry.ServeHTTP(recorder, httptest.NewRequest("GET", "/metrics", nil))
+	return recorder.Body.String()
+}
+
+// blockingWriter is a response writer whose writes wait for release.
+type blockingWriter struct {
+	*httptest.ResponseRecorder
+	writing chan struct{}
+	release chan struct{}
+}
+
+func (b blockingWriter) Write(data []byte) (int, error) {
+	select {
+	case b.writing <- struct{}{}:
+	default:
+	}
+	<-b.release
+	return b.ResponseRecorder.Write(data)
+}
+
+func TestRegistry(t *testing.T) {
+
+	t.Run("should report the hit tier and the backfills of the cache strategy", func(t *testing.T) {
+		cache := unit_test.NewMemoryUnit[string, string]()
+		primary := unit_test.NewMemoryUnit[string, string]()
+		primary.Save(context.Background(), "query", "value")
+
+		orchestrator := pkg.NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{}, nil)
+		orchestrator.AddUnit("cache", cache)
+		orchestrator.AddUnit("primary", primary)
+		orchestrator.SetStandardOrder("cache", "primary")
+
+		registry := NewRegistry()
+		Instrument[string, string](&orchestrator, registry)
+
+		orchestrator.Get("query")
+		orchestrator.Get("query")
+		orchestrator.Get("missing")
+
+		body := scrape(registry)
+		assert.Contains(t, body, "# TYPE storage_orchestrator_get_hits_total counter\n")
+		assert.Contains(t, body, `storage_orchestrator_get_hits_total{strategy="cache",unit="cache",tier="0"} 1`)
+		assert.Contains(t, body, `storage_orchestrator_get_hits_total{strategy="cache",unit="primary",tier="1"} 1`)
+		assert.Contains(t, body, `storage_orchestrator_get_misses_total{strategy="cache"} 1`)
+		assert.Contains(t, body, `storage_orchestrator_backfills_total{strategy="cache",unit="cache"} 1`)
+		assert.Contains(t, body, `storage_orchestrator_operations_total{operation="get",strategy="cache"} 3`)
+		assert.Contains(t, body, `storage_orchestrator_unit_calls_total{unit="cache",operation="save"} 1`)
+		assert.Contains(t, body, `storage_orchestrator_unit_hits_total{unit="cache"} 1`)
+		assert.Contains(t, body, `storage_orchestrator_unit_misses_total{unit="primary"} 1`)
+		assert.Contains(t, body, `storage_orchestrator_operation_duration_seconds_count{operation="get",strategy="cache"} 3`)
+		assert.Contains(t, body, `storage_orchestrator_operation_duration_seconds_bucket{operation="get",strategy="cache",le="+Inf"} 3`)
+		assert.NotContains(t, body, "storage_orchestrator_operation_errors_total")
+	})
+
+	t.Run("should count the errors by class", func(t *testing.T) {
+		slow := unit_test.NewUnitMock()
+		slow.On("Save", "query", "value", mock.Anything).Run(func(args mock.Arguments) {
+			<-args.Get(2).(context.Context).Done()
+		}).Return(context.DeadlineExceeded)
+		broken := unit_test.NewUnitMock()
+		broken.On("Save", "query", "value", mock.Anything).Return(fmt.Errorf("down"))
+
+		orchestrator := pkg.NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{}, nil)
+		orchestrator.AddUnit("slow", slow, protocols.WithUnitTimeout(time.Millisecond))
+		orchestrator.AddUnit("broken", broken)
+
+		registry := NewRegistry()
+		Instrument[string, string](&orchestrator, registry)
+
+		orchestrator.Save("query", "value", func(opt *protocols.SaveOptions) {
+			opt.Targets = []string{"slow"}
+		})
+		orchestrator.Save("query", "value", func(opt *protocols.SaveOptions) {
+			opt.Targets = []string{"broken"}
+		})
+
+		body := scrape(registry)
+		assert.Contains(t, body, `storage_orchestrator_operation_errors_total{operation="save",strategy="sequential",class="timeout"} 1`)
+		assert.Contains(t, body, `storage_orchestrator_operation_errors_total{operation="save",strategy="sequential",class="other"} 1`)
+		assert.Contains(t, body, `storage_orchestrator_unit_errors_total{unit="slow",operation="save",class="timeout"} 1`)
+
+		_, err := orchestrator.GetMany([]string{"query"}, protocols.WithGetStrategy(protocols.Race))
+		assert.Equal(t, "batch_unsupported", ErrorClass(err))
+	})
+
+	t.Run("should not hold up recording while the scrape is written", func(t *testing.T) {
+		registry := NewRegistry()
+		registry.ObserveOperation(protocols.OperationInfo{Operation: protocols.GetOperation, Strategy: "cache"})
+		writer := blockingWriter{ResponseRecorder: httptest.NewRecorder(), writing: make(chan struct{}, 1), release: make(chan struct{})}
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			registry.ServeHTTP(writer, httptest.NewRequest("GET", "/metrics", nil))
+		}()
+		<-writer.writing
+
+		recorded := make(chan struct{})
+		go func() {
+			defer close(recorded)
+			registry.ObserveOperation(protocols.OperationInfo{Operation: protocols.GetOperation, Strategy: "cache"})
+		}()
+		select {
+		case <-recorded:
+		case <-time.After(time.Second):
+			t.Fatal("recording waited for the scrape")
+		}
+
+		close(writer.release)
+		<-done
+	})
+
+	t.Run("should escape label values", func(t *testing.T) {
+		f := newFamily("test_total", "Test.", "counter", nil, "unit")
+		f.add(2, "a\"b\\c\nd")
+
+		recorder := httptest.NewRecorder()
+		f.write(recorder)
+		assert.Equal(t, "# HELP test_total Test.\n# TYPE test_total counter\ntest_total{unit=\"a\\\"b\\\\c\\nd\"} 2\n", recorder.Body.String())
+	})
+}
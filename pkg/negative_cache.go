@@ -0,0 +1,131 @@
+package pkg
+
+import (
+	"sync"
+	"time"
+)
+
+type negativeEntry struct {
+	expires time.Time
+	targets map[string]bool
+}
+
+// negativeCache remembers the keys every target of a Get cleanly missed, for
+// ttl. A Get looks a key up between start and end, and its miss is not
+// recorded when the key was invalidated in between, so a Get racing with a
+// Save of the same key cannot cache a miss the Save made wrong. Invalidations
+// are tracked per key, and only for the keys being looked up, so saving one key
+// does not keep the misses of the others from being recorded.
+type negativeCache struct {
+	mu          sync.Mutex
+	ttl         time.Duration
+	entries     map[string]negativeEntry
+	clock       uint64
+	cleared     uint64
+	lookups     map[string]int
+	invalidated map[string]uint64
+	pruneAt     int
+}
+
+func newNegativeCache(ttl time.Duration) *negativeCache {
+	return &negativeCache{
+		ttl:         ttl,
+		entries:     map[string]negativeEntry{},
+		lookups:     map[string]int{},
+		invalidated: map[string]uint64{},
+		pruneAt:     1024,
+	}
+}
+
+// missed reports whether key is known to be missing from every one of targets.
+func (n *negativeCache) missed(key string, targets []string) bool {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	entry, ok := n.entries[key]
+	if !ok {
+		return false
+	}
+	if time.Now().After(entry.expires) {
+		delete(n.entries, key)
+		return false
+	}
+	for _, target := range targets {
+		if !entry.targets[target] {
+			return false
+		}
+	}
+	return true
+}
+
+// start records that a Get of key began and returns the generation to hand to
+// end.
+func (n *negativeCache) start(key string) uint64 {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.lookups[key]++
+	return n.clock
+}
+
+// end records that the Get of key started at generation finished, and when
+// missed that targets missed it, unless key was invalidated since.
+func (n *negativeCache) end(key string, generation uint64, targets []string, missed bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	outdated := n.invalidated[key] > generation || n.cleared > generation
+	if n.lookups[key]--; n.lookups[key] == 0 {
+		delete(n.lookups, key)
+		delete(n.invalidated, key)
+	}
+	if !missed || outdated {
+		return
+	}
+
+	entry := negativeEntry{expires: time.Now().Add(n.ttl), targets: make(map[string]bool, len(targets))}
+	for _, target := range targets {
+		entry.targets[target] = true
+	}
+	n.entries[key] = entry
+
+	if len(n.entries) >= n.pruneAt {
+		n.prune()
+	}
+}
+
+// prune drops the expired entries. The caller must hold n.mu.
+func (n *negativeCache) prune() {
+	now := time.Now()
+	for key, entry := range n.entries {
+		if now.After(entry.expires) {
+			delete(n.entries, key)
+		}
+	}
+	n.pruneAt = 2 * len(n.entries)
+	if n.pruneAt < 1024 {
+		n.pruneAt = 1024
+	}
+}
+
+func (n *negativeCache) invalidate(keys ...string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.clock++
+	for _, key := range keys {
+		delete(n.entries, key)
+		if n.lookups[key] > 0 {
+			n.invalidated[key] = n.clock
+		}
+	}
+}
+
+func (n *negativeCache) clear() {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	n.clock++
+	n.cleared = n.clock
+	n.entries = map[string]negativeEntry{}
+}
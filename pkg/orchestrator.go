@@ -1,9 +1,17 @@
 package pkg
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"log/slog"
+	"sort"
+	"strconv"
+	"strings"
 	"sync"
+	"time"
 
+	"github.com/joaogabriel01/storage-orchestrator/pkg/decorators"
 	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
 	"github.com/joaogabriel01/storage-orchestrator/pkg/strategies"
 )
@@ -11,57 +19,385 @@ import (
 type Orchestrator[K any, V any] struct {
 	mu               sync.RWMutex
 	units            map[string]protocols.StorageUnit[K, V]
+	unitOptions      map[string]protocols.UnitOptions
+	interceptors     []protocols.Interceptor[K, V]
+	observers        []protocols.OperationObserver
+	tracer           protocols.Tracer
+	logger           *slog.Logger
+	redactKey        protocols.KeyRedactor[K]
+	coalesceKey      protocols.KeyFunc[K]
+	flights          flightGroup[V]
+	missKey          protocols.KeyFunc[K]
+	misses           *negativeCache
 	standardOrder    []string
-	saveStrategies   []protocols.SaveStrategy[K, V]
-	getStrategies    []protocols.GetStrategy[K, V]
-	deleteStrategies []protocols.DeleteStrategy[K, V]
+	saveStrategies   map[protocols.TypeSaveOptions]protocols.SaveStrategy[K, V]
+	getStrategies    map[protocols.TypeGetOptions]protocols.GetStrategy[K, V]
+	deleteStrategies map[protocols.TypeDeleteOptions]protocols.DeleteStrategy[K, V]
 }
 
 func (o *Orchestrator[K, V]) Save(query K, item V, opts ...protocols.SaveOptionsFunc) ([]string, error) {
-	opt := o.defaultSaveOptions()
+	state := o.snapshot()
+	opt := o.defaultSaveOptions(state.standardOrder)
 	for _, fn := range opts {
 		fn(&opt)
 	}
 
-	return o.saveStrategies[opt.HowWillItSave].Save(opt.Context, query, item, o.units, opt.Targets)
+	strategy, err := o.saveStrategy(opt.HowWillItSave)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTargets(state.units, opt.Targets); err != nil {
+		return nil, err
+	}
+	if opt.Targets, err = state.writableTargets(opt.Targets); err != nil {
+		return nil, err
+	}
+	units := state.decorateUnits(string(opt.HowWillItSave), opt.Retry)
+
+	var cancel context.CancelFunc
+	opt.Context, cancel = withTimeout(opt.Context, opt.Timeout)
+	defer cancel()
 
+	state.forgetMisses(query)
+	defer state.forgetMisses(query)
+	opt.Context = protocols.ContextWithSettled(opt.Context, func() {
+		state.forgetMisses(query)
+	})
+
+	var saved []string
+	err = state.run(opt.Context, protocols.SaveOperation, string(opt.HowWillItSave), []K{query}, func(ctx context.Context) (err error) {
+		saved, err = strategy.Save(ctx, query, item, units, opt.Targets, opt.WriteQuorum)
+		return err
+	})
+	return saved, err
 }
 
 func (o *Orchestrator[K, V]) Get(query K, opts ...protocols.GetOptionsFunc) (V, error) {
-	opt := o.defaultGetOptions()
+	state := o.snapshot()
+	opt := o.defaultGetOptions(state.standardOrder)
+	for _, fn := range opts {
+		fn(&opt)
+	}
+
+	var value V
+	strategy, err := o.getStrategy(opt.HowWillItGet)
+	if err != nil {
+		return value, err
+	}
+
+	backfill, err := o.saveStrategy(protocols.Sequential)
+	if err != nil {
+		return value, err
+	}
+
+	if err := checkTargets(state.units, opt.Targets); err != nil {
+		return value, err
+	}
+	units := state.decorateUnits(string(opt.HowWillItGet), opt.Retry)
+
+	var cancel context.CancelFunc
+	opt.Context, cancel = withTimeout(opt.Context, opt.Timeout)
+	defer cancel()
+
+	get := func() (value V, err error) {
+		err = state.run(opt.Context, protocols.GetOperation, string(opt.HowWillItGet), []K{query}, func(ctx context.Context) (err error) {
+			if state.knownMiss(query, opt.Targets) {
+				return fmt.Errorf("negative cache: %w", protocols.ErrNotFound)
+			}
+			generation := state.startLookup(query)
+			defer func() {
+				state.endLookup(query, generation, opt.Targets, errors.Is(err, protocols.ErrNotFound))
+			}()
+			value, err = strategy.Get(ctx, query, units, opt.Targets, backfill, opt.ReadQuorum)
+			return err
+		})
+		return value, err
+	}
+
+	if state.coalesceKey == nil {
+		return get()
+	}
+	// Only gets that would run the same way share a flight: a policy is told
+	// apart by its address, so gets with equal but distinct policies do not.
+	flightKey := strings.Join(append([]string{state.coalesceKey(query), string(opt.HowWillItGet), strconv.Itoa(opt.ReadQuorum),
+		fmt.Sprintf("%p", opt.Retry), opt.Timeout.String()}, opt.Targets...), "\x00")
+	return o.flights.do(opt.Context, flightKey, get)
+}
+
+func (o *Orchestrator[K, V]) Delete(query K, opts ...protocols.DeleteOptionsFunc) ([]string, error) {
+	state := o.snapshot()
+	opt := o.defaultDeleteOptions(state.standardOrder)
+
+	for _, fn := range opts {
+		fn(&opt)
+	}
+
+	strategy, err := o.deleteStrategy(opt.HowWillItDelete)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkTargets(state.units, opt.Targets); err != nil {
+		return nil, err
+	}
+	if opt.Targets, err = state.writableTargets(opt.Targets); err != nil {
+		return nil, err
+	}
+	units := state.decorateUnits(string(opt.HowWillItDelete), opt.Retry)
+
+	var cancel context.CancelFunc
+	opt.Context, cancel = withTimeout(opt.Context, opt.Timeout)
+	defer cancel()
+
+	var deleted []string
+	err = state.run(opt.Context, protocols.DeleteOperation, string(opt.HowWillItDelete), []K{query}, func(ctx context.Context) (err error) {
+		deleted, err = strategy.Delete(ctx, query, units, opt.Targets)
+		return err
+	})
+	return deleted, err
+}
+
+func (o *Orchestrator[K, V]) SaveMany(queries []K, items []V, opts ...protocols.SaveOptionsFunc) ([]string, error) {
+	state := o.snapshot()
+	opt := o.defaultSaveOptions(state.standardOrder)
 	for _, fn := range opts {
 		fn(&opt)
 	}
 
-	return o.getStrategies[opt.HowWillItGet].Get(opt.Context, query, o.units, opt.Targets, o.saveStrategies[protocols.Sequential])
+	if len(queries) != len(items) {
+		return nil, fmt.Errorf("got %v queries for %v items", len(queries), len(items))
+	}
+
+	strategy, err := o.saveStrategy(opt.HowWillItSave)
+	if err != nil {
+		return nil, err
+	}
+
+	batchStrategy, ok := strategy.(protocols.BatchSaveStrategy[K, V])
+	if !ok {
+		return nil, fmt.Errorf("%w: save strategy %q", protocols.ErrBatchUnsupported, opt.HowWillItSave)
+	}
+
+	if err := checkTargets(state.units, opt.Targets); err != nil {
+		return nil, err
+	}
+	if opt.Targets, err = state.writableTargets(opt.Targets); err != nil {
+		return nil, err
+	}
+	units := state.decorateUnits(string(opt.HowWillItSave), opt.Retry)
+
+	var cancel context.CancelFunc
+	opt.Context, cancel = withTimeout(opt.Context, opt.Timeout)
+	defer cancel()
+
+	state.forgetMisses(queries...)
+	defer state.forgetMisses(queries...)
+	opt.Context = protocols.ContextWithSettled(opt.Context, func() {
+		state.forgetMisses(queries...)
+	})
+
+	var saved []string
+	err = state.run(opt.Context, protocols.SaveManyOperation, string(opt.HowWillItSave), queries, func(ctx context.Context) (err error) {
+		saved, err = batchStrategy.SaveMany(ctx, queries, items, units, opt.Targets, opt.WriteQuorum)
+		return err
+	})
+	return saved, err
+}
+
+func (o *Orchestrator[K, V]) GetMany(queries []K, opts ...protocols.GetOptionsFunc) ([]V, error) {
+	state := o.snapshot()
+	opt := o.defaultGetOptions(state.standardOrder)
+	for _, fn := range opts {
+		fn(&opt)
+	}
+
+	strategy, err := o.getStrategy(opt.HowWillItGet)
+	if err != nil {
+		return nil, err
+	}
+
+	batchStrategy, ok := strategy.(protocols.BatchGetStrategy[K, V])
+	if !ok {
+		return nil, fmt.Errorf("%w: get strategy %q", protocols.ErrBatchUnsupported, opt.HowWillItGet)
+	}
+
+	backfill, err := o.saveStrategy(protocols.Sequential)
+	if err != nil {
+		return nil, err
+	}
+
+	batchBackfill, ok := backfill.(protocols.BatchSaveStrategy[K, V])
+	if !ok {
+		return nil, fmt.Errorf("%w: save strategy %q", protocols.ErrBatchUnsupported, protocols.Sequential)
+	}
+
+	if err := checkTargets(state.units, opt.Targets); err != nil {
+		return nil, err
+	}
+	units := state.decorateUnits(string(opt.HowWillItGet), opt.Retry)
+
+	var cancel context.CancelFunc
+	opt.Context, cancel = withTimeout(opt.Context, opt.Timeout)
+	defer cancel()
+
+	var values []V
+	err = state.run(opt.Context, protocols.GetManyOperation, string(opt.HowWillItGet), queries, func(ctx context.Context) (err error) {
+		values, err = batchStrategy.GetMany(ctx, queries, units, opt.Targets, batchBackfill, opt.ReadQuorum)
+		return err
+	})
+	return values, err
+}
+
+func (o *Orchestrator[K, V]) DeleteMany(queries []K, opts ...protocols.DeleteOptionsFunc) ([]string, error) {
+	state := o.snapshot()
+	opt := o.defaultDeleteOptions(state.standardOrder)
+	for _, fn := range opts {
+		fn(&opt)
+	}
+
+	strategy, err := o.deleteStrategy(opt.HowWillItDelete)
+	if err != nil {
+		return nil, err
+	}
+
+	batchStrategy, ok := strategy.(protocols.BatchDeleteStrategy[K, V])
+	if !ok {
+		return nil, fmt.Errorf("%w: delete strategy %q", protocols.ErrBatchUnsupported, opt.HowWillItDelete)
+	}
+
+	if err := checkTargets(state.units, opt.Targets); err != nil {
+		return nil, err
+	}
+	if opt.Targets, err = state.writableTargets(opt.Targets); err != nil {
+		return nil, err
+	}
+	units := state.decorateUnits(string(opt.HowWillItDelete), opt.Retry)
+
+	var cancel context.CancelFunc
+	opt.Context, cancel = withTimeout(opt.Context, opt.Timeout)
+	defer cancel()
+
+	var deleted []string
+	err = state.run(opt.Context, protocols.DeleteManyOperation, string(opt.HowWillItDelete), queries, func(ctx context.Context) (err error) {
+		deleted, err = batchStrategy.DeleteMany(ctx, queries, units, opt.Targets)
+		return err
+	})
+	return deleted, err
 }
 
-func (o *Orchestrator[K, V]) Delete(query K, opts ...protocols.DeleteOptionsFunc) error {
-	opt := o.defaultDeleteOptions()
+// AddUnit, RemoveUnit and ReplaceUnit never modify the units map in place: they
+// swap in a modified copy, so operations already running keep the units they
+// started with.
+func (o *Orchestrator[K, V]) AddUnit(storageName string, storage protocols.StorageUnit[K, V], opts ...protocols.UnitOptionsFunc) error {
+	var opt protocols.UnitOptions
+	for _, fn := range opts {
+		fn(&opt)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	units := o.copyUnits()
+	units[storageName] = storage
+	o.units = units
+	if o.misses != nil {
+		o.misses.clear()
+	}
 
+	unitOptions := o.copyUnitOptions()
+	unitOptions[storageName] = opt
+	o.unitOptions = unitOptions
+	return nil
+}
+
+func (o *Orchestrator[K, V]) RemoveUnit(storageName string, opts ...protocols.RemoveOptionsFunc) error {
+	opt := o.defaultRemoveOptions()
 	for _, fn := range opts {
 		fn(&opt)
 	}
 
-	return o.deleteStrategies[opt.HowWillItDelete].Delete(opt.Context, query, o.units, opt.Targets)
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if _, exists := o.units[storageName]; !exists {
+		return fmt.Errorf("%w: %v", protocols.ErrUnitNotFound, storageName)
+	}
+
+	order := make([]string, 0, len(o.standardOrder))
+	for _, target := range o.standardOrder {
+		if target != storageName {
+			order = append(order, target)
+		}
+	}
+
+	if len(order) != len(o.standardOrder) && opt.HowWillItRemove == protocols.RefuseIfInStandardOrder {
+		return fmt.Errorf("%w: %v", protocols.ErrUnitInUse, storageName)
+	}
+
+	units := o.copyUnits()
+	delete(units, storageName)
+	o.units = units
+
+	unitOptions := o.copyUnitOptions()
+	delete(unitOptions, storageName)
+	o.unitOptions = unitOptions
+	o.standardOrder = order
+	return nil
 }
 
-func (o *Orchestrator[K, V]) AddUnit(storageName string, storage protocols.StorageUnit[K, V]) error {
+func (o *Orchestrator[K, V]) ReplaceUnit(storageName string, storage protocols.StorageUnit[K, V]) error {
 	o.mu.Lock()
 	defer o.mu.Unlock()
-	o.units[storageName] = storage
+
+	if _, exists := o.units[storageName]; !exists {
+		return fmt.Errorf("%w: %v", protocols.ErrUnitNotFound, storageName)
+	}
+
+	units := o.copyUnits()
+	units[storageName] = storage
+	o.units = units
+	if o.misses != nil {
+		o.misses.clear()
+	}
 	return nil
 }
 
+// GetUnitInfo returns the options unitName was added with; units passed to
+// NewOrchestrator have none.
+func (o *Orchestrator[K, V]) GetUnitInfo(unitName string) (protocols.UnitInfo, error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	if _, exists := o.units[unitName]; !exists {
+		return protocols.UnitInfo{}, protocols.ErrUnitNotFound
+	}
+
+	info := protocols.UnitInfo{Name: unitName, UnitOptions: o.unitOptions[unitName]}
+	info.Tags = append([]string(nil), info.Tags...)
+	return info, nil
+}
+
 func (o *Orchestrator[K, V]) GetUnits() (map[string]protocols.StorageUnit[K, V], error) {
 	o.mu.RLock()
 	defer o.mu.RUnlock()
 
-	unitsCopy := make(map[string]protocols.StorageUnit[K, V])
-	for k, v := range o.units {
-		unitsCopy[k] = v
+	return o.copyUnits(), nil
+}
+
+// DecoratedUnits returns a function listing the units the way operations call
+// them: behind their timeouts, retry policies and the interceptors, tracing and
+// logging included, with the calls attributed to strategy. It is meant for
+// strategies that call units outside of an operation, such as the flushes of a
+// strategies.WriteBehindSaveStrategy, and lists the units as they are when
+// called.
+func (o *Orchestrator[K, V]) DecoratedUnits(strategy string) func() (map[string]protocols.StorageUnit[K, V], error) {
+	return func() (map[string]protocols.StorageUnit[K, V], error) {
+		units := o.snapshot().decorateUnits(strategy, nil)
+		decorated := make(map[string]protocols.StorageUnit[K, V], len(units))
+		for name, unit := range units {
+			decorated[name] = unit
+		}
+		return decorated, nil
 	}
-	return unitsCopy, nil
 }
 
 func (o *Orchestrator[K, V]) GetUnit(unitName string) (protocols.StorageUnit[K, V], error) {
@@ -69,18 +405,21 @@ func (o *Orchestrator[K, V]) GetUnit(unitName string) (protocols.StorageUnit[K,
 	defer o.mu.RUnlock()
 	unit, exists := o.units[unitName]
 	if !exists {
-		return nil, fmt.Errorf("unit not found")
+		return nil, protocols.ErrUnitNotFound
 	}
 
 	return unit, nil
 }
 
 func (o *Orchestrator[K, V]) SetStandardOrder(targets ...string) error {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
 	order := make([]string, len(targets))
 	for c, target := range targets {
 		_, ok := o.units[target]
 		if !ok {
-			return fmt.Errorf("this unit does not exist: %v", target)
+			return fmt.Errorf("%w: %v", protocols.ErrUnitNotFound, target)
 		}
 		order[c] = target
 	}
@@ -88,30 +427,441 @@ func (o *Orchestrator[K, V]) SetStandardOrder(targets ...string) error {
 	return nil
 }
 
+// Use adds interceptors around every call made to the units. The first
+// interceptor added is the outermost one.
+func (o *Orchestrator[K, V]) Use(interceptors ...protocols.Interceptor[K, V]) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	chain := make([]protocols.Interceptor[K, V], 0, len(o.interceptors)+len(interceptors))
+	chain = append(chain, o.interceptors...)
+	o.interceptors = append(chain, interceptors...)
+}
+
+// Observe adds observers that are told about every operation once its
+// strategy returned.
+func (o *Orchestrator[K, V]) Observe(observers ...protocols.OperationObserver) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	all := make([]protocols.OperationObserver, 0, len(o.observers)+len(observers))
+	all = append(all, o.observers...)
+	o.observers = append(all, observers...)
+}
+
+// SetTracer makes every operation emit a span through tracer, with a child
+// span for each call made to the units. A nil tracer turns tracing off.
+func (o *Orchestrator[K, V]) SetTracer(tracer protocols.Tracer) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.tracer = tracer
+}
+
+// SetLogger makes the orchestrator log its operations and unit calls to
+// logger. Keys are hashed with protocols.HashKey unless another redactor is
+// given. A nil logger turns logging off.
+func (o *Orchestrator[K, V]) SetLogger(logger *slog.Logger, opts ...protocols.LoggerOptionsFunc[K]) {
+	opt := protocols.LoggerOptions[K]{RedactKey: protocols.HashKey[K]}
+	for _, fn := range opts {
+		fn(&opt)
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.logger = logger
+	o.redactKey = opt.RedactKey
+}
+
+// SetCoalescing makes concurrent Gets of the same key, with the same strategy
+// and targets, share a single lookup and backfill, run with the context of the
+// first of them. key tells which keys are the same; nil turns coalescing off.
+// The callers share the value returned, so values they modify must be copied.
+func (o *Orchestrator[K, V]) SetCoalescing(key protocols.KeyFunc[K]) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.coalesceKey = key
+}
+
+// SetNegativeCache makes the orchestrator remember, for ttl, the keys every
+// target of a Get cleanly missed, and answer later Gets of them over the same
+// targets, or some of them, with ErrNotFound without calling the units. Saving
+// a key forgets its misses, and AddUnit and ReplaceUnit forget all of them. key
+// tells which keys are the same; a nil key or a ttl of zero turns the negative
+// cache off.
+func (o *Orchestrator[K, V]) SetNegativeCache(ttl time.Duration, key protocols.KeyFunc[K]) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+
+	if ttl <= 0 || key == nil {
+		o.missKey, o.misses = nil, nil
+		return
+	}
+	o.missKey, o.misses = key, newNegativeCache(ttl)
+}
+
+func (o *Orchestrator[K, V]) RegisterSaveStrategy(name protocols.TypeSaveOptions, strategy protocols.SaveStrategy[K, V]) error {
+	if name == "" || strategy == nil {
+		return fmt.Errorf("a save strategy needs a name and an implementation")
+	}
+
+	if deferred, ok := strategy.(protocols.DeferredSaveStrategy[K, V]); ok {
+		deferred.OnDeferredWrite(func(queries ...K) {
+			o.snapshot().forgetMisses(queries...)
+		})
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.saveStrategies[name] = strategy
+	return nil
+}
+
+func (o *Orchestrator[K, V]) RegisterGetStrategy(name protocols.TypeGetOptions, strategy protocols.GetStrategy[K, V]) error {
+	if name == "" || strategy == nil {
+		return fmt.Errorf("a get strategy needs a name and an implementation")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.getStrategies[name] = strategy
+	return nil
+}
+
+func (o *Orchestrator[K, V]) RegisterDeleteStrategy(name protocols.TypeDeleteOptions, strategy protocols.DeleteStrategy[K, V]) error {
+	if name == "" || strategy == nil {
+		return fmt.Errorf("a delete strategy needs a name and an implementation")
+	}
+
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.deleteStrategies[name] = strategy
+	return nil
+}
+
+// topology is what an operation runs against. The maps and the interceptors
+// are never modified after being published, so they are safe to hand to
+// strategies without holding the lock; the order is copied because option
+// functions may change the targets in place.
+type topology[K any, V any] struct {
+	units         map[string]protocols.StorageUnit[K, V]
+	unitOptions   map[string]protocols.UnitOptions
+	standardOrder []string
+	interceptors  []protocols.Interceptor[K, V]
+	observers     []protocols.OperationObserver
+	tracer        protocols.Tracer
+	logger        *slog.Logger
+	redactKey     protocols.KeyRedactor[K]
+	coalesceKey   protocols.KeyFunc[K]
+	missKey       protocols.KeyFunc[K]
+	misses        *negativeCache
+}
+
+func (o *Orchestrator[K, V]) snapshot() topology[K, V] {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	standardOrder := make([]string, len(o.standardOrder))
+	copy(standardOrder, o.standardOrder)
+	return topology[K, V]{
+		units:         o.units,
+		unitOptions:   o.unitOptions,
+		standardOrder: standardOrder,
+		interceptors:  o.interceptors,
+		observers:     o.observers,
+		tracer:        o.tracer,
+		logger:        o.logger,
+		redactKey:     o.redactKey,
+		coalesceKey:   o.coalesceKey,
+		missKey:       o.missKey,
+		misses:        o.misses,
+	}
+}
+
+// decorateUnits returns the units an operation hands to its strategy, wrapping
+// each unit with the behaviour configured for it. From the inside out: the unit
+// timeout, the interceptors and the retry policy, so the interceptors see every
+// attempt. The unit call spans and logs come first in the interceptors. The
+// operation's retry policy takes precedence over the one set on the unit.
+func (t topology[K, V]) decorateUnits(strategy string, retry *protocols.RetryPolicy) map[string]protocols.StorageUnit[K, V] {
+	decorated := make(map[string]protocols.StorageUnit[K, V], len(t.units))
+	changed := false
+
+	var interceptors []protocols.Interceptor[K, V]
+	if t.tracer != nil {
+		interceptors = append(interceptors, decorators.TracingInterceptor[K, V](t.tracer, strategy))
+	}
+	if t.logger != nil {
+		interceptors = append(interceptors, decorators.LoggingInterceptor[K, V](t.logger, strategy, t.redactKey))
+	}
+	interceptors = append(interceptors, t.interceptors...)
+
+	for name, unit := range t.units {
+		if timeout := t.unitOptions[name].Timeout; timeout > 0 {
+			unit = decorators.NewTimeoutUnit(name, unit, timeout)
+			changed = true
+		}
+
+		if len(interceptors) > 0 {
+			unit = decorators.NewInterceptedUnit(name, unit, interceptors...)
+			changed = true
+		}
+
+		policy := t.unitOptions[name].Retry
+		if retry != nil {
+			policy = retry
+		}
+		if policy != nil {
+			unit = decorators.NewRetryUnit(unit, *policy)
+			changed = true
+		}
+		decorated[name] = unit
+	}
+
+	if !changed {
+		return t.units
+	}
+	return decorated
+}
+
+// knownMiss reports whether the negative cache knows every target misses query.
+func (t topology[K, V]) knownMiss(query K, targets []string) bool {
+	return t.misses != nil && t.misses.missed(t.missKey(query), targets)
+}
+
+// startLookup tells the negative cache a Get of query began, and returns the
+// generation to hand to endLookup.
+func (t topology[K, V]) startLookup(query K) uint64 {
+	if t.misses == nil {
+		return 0
+	}
+	return t.misses.start(t.missKey(query))
+}
+
+// endLookup tells the negative cache the Get of query finished, and whether
+// every target missed it.
+func (t topology[K, V]) endLookup(query K, generation uint64, targets []string, missed bool) {
+	if t.misses != nil {
+		t.misses.end(t.missKey(query), generation, targets, missed)
+	}
+}
+
+// forgetMisses drops the misses recorded for queries. Saves call it before and
+// after writing, so a Get running meanwhile does not record a miss either, and
+// again once the writes still running after the save returned settled.
+func (t topology[K, V]) forgetMisses(queries ...K) {
+	if t.misses == nil {
+		return
+	}
+	keys := make([]string, len(queries))
+	for c, query := range queries {
+		keys[c] = t.missKey(query)
+	}
+	t.misses.invalidate(keys...)
+}
+
+// run calls the strategy of an operation through call, within the span of the
+// operation, then logs the operation and reports it to the observers. The
+// context carries the options of the units to the strategy, and for get
+// operations a protocols.GetReport for the strategy to fill in.
+func (t topology[K, V]) run(ctx context.Context, operation protocols.UnitOperation, strategy string, queries []K, call func(ctx context.Context) error) error {
+	info := protocols.OperationInfo{Operation: operation, Strategy: strategy, Keys: len(queries), Start: time.Now()}
+	ctx = protocols.ContextWithUnitOptions(ctx, t.unitOptions)
+	if operation == protocols.GetOperation || operation == protocols.GetManyOperation {
+		info.Get = &protocols.GetReport{}
+		ctx = protocols.ContextWithGetReport(ctx, info.Get)
+	}
+
+	var span protocols.Span
+	if t.tracer != nil {
+		ctx, span = t.tracer.Start(ctx, "orchestrator."+string(operation),
+			protocols.Attribute{Key: protocols.AttributeOperation, Value: string(operation)},
+			protocols.Attribute{Key: protocols.AttributeStrategy, Value: strategy},
+			protocols.Attribute{Key: protocols.AttributeKeys, Value: len(queries)},
+		)
+	}
+
+	info.Err = call(ctx)
+	info.Duration = time.Since(info.Start)
+
+	if span != nil {
+		outcome := protocols.Outcome(info.Err)
+		span.SetAttributes(protocols.Attribute{Key: protocols.AttributeOutcome, Value: outcome})
+		if outcome == protocols.OutcomeError {
+			span.RecordError(info.Err)
+		}
+		span.End()
+	}
+
+	if t.logger != nil {
+		t.log(ctx, info, queries)
+	}
+	for _, observe := range t.observers {
+		observe(info)
+	}
+	return info.Err
+}
+
+// log logs a finished operation: at error level when it failed, at debug level
+// otherwise, and each backfill that failed at warn level.
+func (t topology[K, V]) log(ctx context.Context, info protocols.OperationInfo, queries []K) {
+	attributes := []slog.Attr{
+		slog.String("operation", string(info.Operation)),
+		slog.String("strategy", info.Strategy),
+	}
+	if len(queries) == 1 {
+		attributes = append(attributes, slog.String("key", t.redactKey(queries[0])))
+	} else {
+		attributes = append(attributes, slog.Int("keys", len(queries)))
+	}
+
+	failedBackfills := info.Get.FailedBackfills()
+	for _, unit := range sortedKeys(failedBackfills) {
+		backfill := append(attributes[:len(attributes):len(attributes)], slog.String("unit", unit), slog.Any("error", failedBackfills[unit]))
+		t.logger.LogAttrs(ctx, slog.LevelWarn, "backfill failed", backfill...)
+	}
+
+	attributes = append(attributes, slog.Duration("duration", info.Duration))
+	level := slog.LevelDebug
+	if protocols.Outcome(info.Err) == protocols.OutcomeError {
+		level = slog.LevelError
+	}
+	if info.Err != nil {
+		attributes = append(attributes, slog.Any("error", info.Err))
+	}
+	t.logger.LogAttrs(ctx, level, "operation", attributes...)
+}
+
+func sortedKeys[T any](m map[string]T) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func withTimeout(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// writableTargets drops the read-only units from the targets of a write, and
+// fails when all of them are read-only.
+func (t topology[K, V]) writableTargets(targets []string) ([]string, error) {
+	writable := make([]string, 0, len(targets))
+	for _, target := range targets {
+		if !t.unitOptions[target].ReadOnly {
+			writable = append(writable, target)
+		}
+	}
+	if len(writable) == 0 {
+		return nil, fmt.Errorf("%w: %v", protocols.ErrReadOnlyUnit, strings.Join(targets, ", "))
+	}
+	return writable, nil
+}
+
+func checkTargets[K any, V any](units map[string]protocols.StorageUnit[K, V], targets []string) error {
+	if len(targets) == 0 {
+		return protocols.ErrNoTargets
+	}
+
+	for _, target := range targets {
+		if _, ok := units[target]; !ok {
+			return fmt.Errorf("%w: %v", protocols.ErrUnitNotFound, target)
+		}
+	}
+	return nil
+}
+
+func (o *Orchestrator[K, V]) copyUnits() map[string]protocols.StorageUnit[K, V] {
+	unitsCopy := make(map[string]protocols.StorageUnit[K, V], len(o.units)+1)
+	for k, v := range o.units {
+		unitsCopy[k] = v
+	}
+	return unitsCopy
+}
+
+func (o *Orchestrator[K, V]) copyUnitOptions() map[string]protocols.UnitOptions {
+	optionsCopy := make(map[string]protocols.UnitOptions, len(o.unitOptions)+1)
+	for k, v := range o.unitOptions {
+		optionsCopy[k] = v
+	}
+	return optionsCopy
+}
+
+func (o *Orchestrator[K, V]) saveStrategy(name protocols.TypeSaveOptions) (protocols.SaveStrategy[K, V], error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	strategy, ok := o.saveStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: save strategy %q", protocols.ErrUnknownStrategy, name)
+	}
+	return strategy, nil
+}
+
+func (o *Orchestrator[K, V]) getStrategy(name protocols.TypeGetOptions) (protocols.GetStrategy[K, V], error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	strategy, ok := o.getStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: get strategy %q", protocols.ErrUnknownStrategy, name)
+	}
+	return strategy, nil
+}
+
+func (o *Orchestrator[K, V]) deleteStrategy(name protocols.TypeDeleteOptions) (protocols.DeleteStrategy[K, V], error) {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	strategy, ok := o.deleteStrategies[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: delete strategy %q", protocols.ErrUnknownStrategy, name)
+	}
+	return strategy, nil
+}
+
 func NewOrchestrator[K any, V any](units map[string]protocols.StorageUnit[K, V], standardOrder []string) Orchestrator[K, V] {
-	var saveStragies []protocols.SaveStrategy[K, V]
-	var getStrategies []protocols.GetStrategy[K, V]
-	var deleteStrategies []protocols.DeleteStrategy[K, V]
+	saveStrategies := map[protocols.TypeSaveOptions]protocols.SaveStrategy[K, V]{
+		protocols.Sequential: &strategies.SequentialSaveStrategy[K, V]{},
+		protocols.Parallel:   &strategies.ParallelSaveStrategy[K, V]{},
+		protocols.Quorum:     &strategies.QuorumSaveStrategy[K, V]{},
 
-	sequentialSave := strategies.SequentialSaveStrategy[K, V]{}
-	parallelSave := strategies.ParallelSaveStrategy[K, V]{}
-	saveStragies = append(saveStragies, &sequentialSave, &parallelSave)
+		protocols.WriteAround:    &strategies.WriteAroundSaveStrategy[K, V]{},
+		protocols.SourceOnly:     &strategies.WriteAroundSaveStrategy[K, V]{KeepCaches: true},
+		protocols.Atomic:         &strategies.RollbackSaveStrategy[K, V]{},
+		protocols.AtomicParallel: &strategies.RollbackSaveStrategy[K, V]{Strategy: &strategies.ParallelSaveStrategy[K, V]{}},
+		protocols.TwoPhase:       &strategies.TwoPhaseSaveStrategy[K, V]{},
+	}
 
-	cacheGet := strategies.CacheGetStrategy[K, V]{}
-	getStrategies = append(getStrategies, &cacheGet)
+	getStrategies := map[protocols.TypeGetOptions]protocols.GetStrategy[K, V]{
+		protocols.Cache:     &strategies.CacheGetStrategy[K, V]{},
+		protocols.Race:      &strategies.RaceGetStrategy[K, V]{},
+		protocols.QuorumGet: &strategies.QuorumGetStrategy[K, V]{},
 
-	sequentialDelete := strategies.SequentialDeleteStrategy[K, V]{}
-	deleteStrategies = append(deleteStrategies, &sequentialDelete)
-	return Orchestrator[K, V]{
-		units:            units,
-		standardOrder:    standardOrder,
-		saveStrategies:   saveStragies,
-		getStrategies:    getStrategies,
-		deleteStrategies: deleteStrategies,
+		protocols.StaleWhileRevalidate: &strategies.StaleWhileRevalidateGetStrategy[K, V]{},
+	}
+
+	deleteStrategies := map[protocols.TypeDeleteOptions]protocols.DeleteStrategy[K, V]{
+		protocols.SequentialDelete: &strategies.SequentialDeleteStrategy[K, V]{},
+		protocols.ParallelDelete:   &strategies.ParallelDeleteStrategy[K, V]{},
+		protocols.BestEffortDelete: &strategies.BestEffortDeleteStrategy[K, V]{},
 	}
+
+	return NewOrchestratorWithParameters(units, standardOrder, saveStrategies, getStrategies, deleteStrategies)
 }
 
-func NewOrchestratorWithParameters[K any, V any](units map[string]protocols.StorageUnit[K, V], standardOrder []string, saveStrategies []protocols.SaveStrategy[K, V], getStrategies []protocols.GetStrategy[K, V], deleteStrategies []protocols.DeleteStrategy[K, V]) Orchestrator[K, V] {
+func NewOrchestratorWithParameters[K any, V any](units map[string]protocols.StorageUnit[K, V], standardOrder []string, saveStrategies map[protocols.TypeSaveOptions]protocols.SaveStrategy[K, V], getStrategies map[protocols.TypeGetOptions]protocols.GetStrategy[K, V], deleteStrategies map[protocols.TypeDeleteOptions]protocols.DeleteStrategy[K, V]) Orchestrator[K, V] {
+	if saveStrategies == nil {
+		saveStrategies = map[protocols.TypeSaveOptions]protocols.SaveStrategy[K, V]{}
+	}
+	if getStrategies == nil {
+		getStrategies = map[protocols.TypeGetOptions]protocols.GetStrategy[K, V]{}
+	}
+	if deleteStrategies == nil {
+		deleteStrategies = map[protocols.TypeDeleteOptions]protocols.DeleteStrategy[K, V]{}
+	}
+
 	return Orchestrator[K, V]{
 		units:            units,
 		standardOrder:    standardOrder,
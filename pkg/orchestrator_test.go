@@ -1,9 +1,21 @@
 package pkg
 
 import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	"github.com/joaogabriel01/storage-orchestrator/pkg/queues"
+	"github.com/joaogabriel01/storage-orchestrator/pkg/strategies"
 	strategies_mock "github.com/joaogabriel01/storage-orchestrator/pkg/strategies/test"
 	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
 	"github.com/stretchr/testify/assert"
@@ -27,13 +39,15 @@ func setupOrchestrator() {
 	getStrategy = strategies_mock.MockGetStrategy{}
 	deleteStrategy = strategies_mock.MockDeleteStrategy{}
 
-	var saveStrategiesTyped []protocols.SaveStrategy[string, string]
-	var getStrategiesTyped []protocols.GetStrategy[string, string]
-	var deleteStrategiesTyped []protocols.DeleteStrategy[string, string]
-
-	saveStrategiesTyped = append(saveStrategiesTyped, &saveStrategy)
-	getStrategiesTyped = append(getStrategiesTyped, &getStrategy)
-	deleteStrategiesTyped = append(deleteStrategiesTyped, &deleteStrategy)
+	saveStrategiesTyped := map[protocols.TypeSaveOptions]protocols.SaveStrategy[string, string]{
+		protocols.Sequential: &saveStrategy,
+	}
+	getStrategiesTyped := map[protocols.TypeGetOptions]protocols.GetStrategy[string, string]{
+		protocols.Cache: &getStrategy,
+	}
+	deleteStrategiesTyped := map[protocols.TypeDeleteOptions]protocols.DeleteStrategy[string, string]{
+		protocols.SequentialDelete: &deleteStrategy,
+	}
 
 	orchestrator = NewOrchestratorWithParameters[string, string](units, standardOrder, saveStrategiesTyped, getStrategiesTyped, deleteStrategiesTyped)
 
@@ -57,7 +71,7 @@ func TestOrchestratorUnitOperations(t *testing.T) {
 		assert.Same(t, mock1, mock1Received)
 
 		mockNotFound, err := orchestrator.GetUnit("non-existentMock")
-		assert.EqualError(t, err, "unit not found")
+		assert.ErrorIs(t, err, protocols.ErrUnitNotFound)
 		assert.Equal(t, nil, mockNotFound)
 
 	})
@@ -71,8 +85,60 @@ func TestOrchestratorUnitOperations(t *testing.T) {
 	t.Run("should return error when there are no units", func(t *testing.T) {
 		setupOrchestrator()
 		err := orchestrator.SetStandardOrder("mock1", "mock2", "mock3")
-		assert.ErrorContains(t, err, "this unit does not exist: mock3")
+		assert.ErrorIs(t, err, protocols.ErrUnitNotFound)
+		assert.ErrorContains(t, err, "mock3")
+
+	})
+
+	t.Run("should remove the unit and drop it from the standard order", func(t *testing.T) {
+		setupOrchestrator()
+
+		err := orchestrator.RemoveUnit("mock1")
+		assert.NoError(t, err)
+
+		_, err = orchestrator.GetUnit("mock1")
+		assert.ErrorIs(t, err, protocols.ErrUnitNotFound)
+		assert.Equal(t, []string{"mock2"}, orchestrator.standardOrder)
+	})
+
+	t.Run("should refuse to remove a unit in the standard order when asked to", func(t *testing.T) {
+		setupOrchestrator()
+
+		err := orchestrator.RemoveUnit("mock1", protocols.WithRemovePolicy(protocols.RefuseIfInStandardOrder))
+		assert.ErrorIs(t, err, protocols.ErrUnitInUse)
+
+		unit, err := orchestrator.GetUnit("mock1")
+		assert.NoError(t, err)
+		assert.Same(t, mock1, unit)
+		assert.Equal(t, []string{"mock1", "mock2"}, orchestrator.standardOrder)
+
+		assert.NoError(t, orchestrator.SetStandardOrder("mock2"))
+		assert.NoError(t, orchestrator.RemoveUnit("mock1", protocols.WithRemovePolicy(protocols.RefuseIfInStandardOrder)))
+	})
+
+	t.Run("should return error when removing or replacing a unit that does not exist", func(t *testing.T) {
+		setupOrchestrator()
+
+		err := orchestrator.RemoveUnit("mock3")
+		assert.ErrorIs(t, err, protocols.ErrUnitNotFound)
+
+		err = orchestrator.ReplaceUnit("mock3", unit_test.NewUnitMock())
+		assert.ErrorIs(t, err, protocols.ErrUnitNotFound)
+	})
+
+	t.Run("should replace the unit without changing the units of running operations", func(t *testing.T) {
+		setupOrchestrator()
+		inFlight := orchestrator.units
+		replacement := unit_test.NewUnitMock()
+
+		err := orchestrator.ReplaceUnit("mock1", replacement)
+		assert.NoError(t, err)
 
+		unit, err := orchestrator.GetUnit("mock1")
+		assert.NoError(t, err)
+		assert.Same(t, replacement, unit)
+		assert.Same(t, mock1, inFlight["mock1"])
+		assert.Equal(t, []string{"mock1", "mock2"}, orchestrator.standardOrder)
 	})
 }
 
@@ -99,6 +165,17 @@ func TestOrchestratorStrategies(t *testing.T) {
 
 	})
 
+	t.Run("should pass the write quorum to the save strategy", func(t *testing.T) {
+		setupOrchestrator()
+		saveStrategy.On("Save", mock.Anything, "query", "value", orchestrator.units, []string{"mock1", "mock2"}, []any{2}).Return([]string{"mock1", "mock2"}, nil)
+		saved, err := orchestrator.Save("query", "value", protocols.WithWriteQuorum(2), func(opt *protocols.SaveOptions) {
+			opt.HowWillItSave = protocols.Sequential
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mock1", "mock2"}, saved)
+		saveStrategy.AssertExpectations(t)
+	})
+
 	t.Run("should call get strategy without opt func", func(t *testing.T) {
 		setupOrchestrator()
 		getStrategy.On("Get", mock.Anything, "query", orchestrator.units, []string{"mock1", "mock2"}, mock.Anything).Return("value", nil)
@@ -119,12 +196,24 @@ func TestOrchestratorStrategies(t *testing.T) {
 		getStrategy.AssertExpectations(t)
 	})
 
+	t.Run("should pass the save strategy and the read quorum to the get strategy", func(t *testing.T) {
+		setupOrchestrator()
+		getStrategy.On("Get", mock.Anything, "query", orchestrator.units, []string{"mock1", "mock2"}, []any{&saveStrategy, 2}).Return("value", nil)
+		value, err := orchestrator.Get("query", protocols.WithReadQuorum(2), func(opt *protocols.GetOptions) {
+			opt.HowWillItGet = protocols.Cache
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+		getStrategy.AssertExpectations(t)
+	})
+
 	t.Run("should call delete strategy without opt func", func(t *testing.T) {
 		setupOrchestrator()
 
-		deleteStrategy.On("Delete", mock.Anything, "query", orchestrator.units, []string{"mock1", "mock2"}, mock.Anything).Return(nil)
-		err := orchestrator.Delete("query")
+		deleteStrategy.On("Delete", mock.Anything, "query", orchestrator.units, []string{"mock1", "mock2"}, mock.Anything).Return([]string{"mock1", "mock2"}, nil)
+		deleted, err := orchestrator.Delete("query")
 		assert.NoError(t, err)
+		assert.Equal(t, []string{"mock1", "mock2"}, deleted)
 		deleteStrategy.AssertExpectations(t)
 
 	})
@@ -132,12 +221,954 @@ func TestOrchestratorStrategies(t *testing.T) {
 	t.Run("should call delete strategy with opt func", func(t *testing.T) {
 		setupOrchestrator()
 
-		deleteStrategy.On("Delete", mock.Anything, "query", orchestrator.units, []string{"mock1"}, mock.Anything).Return(nil)
-		err := orchestrator.Delete("query", func(opt *protocols.DeleteOptions) {
+		deleteStrategy.On("Delete", mock.Anything, "query", orchestrator.units, []string{"mock1"}, mock.Anything).Return([]string{"mock1"}, nil)
+		deleted, err := orchestrator.Delete("query", func(opt *protocols.DeleteOptions) {
 			opt.Targets = []string{"mock1"}
 		})
 		assert.NoError(t, err)
+		assert.Equal(t, []string{"mock1"}, deleted)
 		deleteStrategy.AssertExpectations(t)
 
 	})
+
+	t.Run("should return error when the strategy is unknown", func(t *testing.T) {
+		setupOrchestrator()
+
+		_, err := orchestrator.Save("query", "value", func(opt *protocols.SaveOptions) {
+			opt.HowWillItSave = protocols.Parallel
+		})
+		assert.ErrorIs(t, err, protocols.ErrUnknownStrategy)
+
+		_, err = orchestrator.Get("query", func(opt *protocols.GetOptions) {
+			opt.HowWillItGet = protocols.Race
+		})
+		assert.ErrorIs(t, err, protocols.ErrUnknownStrategy)
+
+		_, err = orchestrator.Delete("query", func(opt *protocols.DeleteOptions) {
+			opt.HowWillItDelete = protocols.BestEffortDelete
+		})
+		assert.ErrorIs(t, err, protocols.ErrUnknownStrategy)
+	})
+
+	t.Run("should return error when there are no targets", func(t *testing.T) {
+		setupOrchestrator()
+		noTargets := []string{}
+
+		_, err := orchestrator.Save("query", "value", func(opt *protocols.SaveOptions) {
+			opt.Targets = noTargets
+		})
+		assert.ErrorIs(t, err, protocols.ErrNoTargets)
+
+		_, err = orchestrator.Get("query", func(opt *protocols.GetOptions) {
+			opt.Targets = noTargets
+		})
+		assert.ErrorIs(t, err, protocols.ErrNoTargets)
+
+		_, err = orchestrator.Delete("query", func(opt *protocols.DeleteOptions) {
+			opt.Targets = noTargets
+		})
+		assert.ErrorIs(t, err, protocols.ErrNoTargets)
+
+		saveStrategy.AssertNotCalled(t, "Save")
+		getStrategy.AssertNotCalled(t, "Get")
+		deleteStrategy.AssertNotCalled(t, "Delete")
+	})
+
+	t.Run("should call a registered strategy selected by name", func(t *testing.T) {
+		setupOrchestrator()
+		customSave := strategies_mock.MockSaveStrategy{}
+		customGet := strategies_mock.MockGetStrategy{}
+		customDelete := strategies_mock.MockDeleteStrategy{}
+
+		assert.NoError(t, orchestrator.RegisterSaveStrategy("custom", &customSave))
+		assert.NoError(t, orchestrator.RegisterGetStrategy("custom", &customGet))
+		assert.NoError(t, orchestrator.RegisterDeleteStrategy("custom", &customDelete))
+
+		customSave.On("Save", mock.Anything, "query", "value", orchestrator.units, []string{"mock1", "mock2"}, mock.Anything).Return([]string{"mock1", "mock2"}, nil)
+		customGet.On("Get", mock.Anything, "query", orchestrator.units, []string{"mock1", "mock2"}, mock.Anything).Return("value", nil)
+		customDelete.On("Delete", mock.Anything, "query", orchestrator.units, []string{"mock1", "mock2"}, mock.Anything).Return([]string{"mock1", "mock2"}, nil)
+
+		_, err := orchestrator.Save("query", "value", protocols.WithSaveStrategy("custom"))
+		assert.NoError(t, err)
+		_, err = orchestrator.Get("query", protocols.WithGetStrategy("custom"))
+		assert.NoError(t, err)
+		_, err = orchestrator.Delete("query", protocols.WithDeleteStrategy("custom"))
+		assert.NoError(t, err)
+
+		customSave.AssertExpectations(t)
+		customGet.AssertExpectations(t)
+		customDelete.AssertExpectations(t)
+		saveStrategy.AssertNotCalled(t, "Save")
+		getStrategy.AssertNotCalled(t, "Get")
+		deleteStrategy.AssertNotCalled(t, "Delete")
+	})
+
+	t.Run("should return error when registering a strategy without name or implementation", func(t *testing.T) {
+		setupOrchestrator()
+
+		assert.Error(t, orchestrator.RegisterSaveStrategy("", &saveStrategy))
+		assert.Error(t, orchestrator.RegisterGetStrategy("custom", nil))
+		assert.Error(t, orchestrator.RegisterDeleteStrategy("", nil))
+	})
+
+	t.Run("should describe the unknown strategy instead of panicking", func(t *testing.T) {
+		setupOrchestrator()
+
+		_, err := orchestrator.Save("query", "value", protocols.WithSaveStrategy("missing"))
+		assert.ErrorIs(t, err, protocols.ErrUnknownStrategy)
+		assert.ErrorContains(t, err, `save strategy "missing"`)
+	})
+}
+
+func TestOrchestratorRetry(t *testing.T) {
+
+	t.Run("should retry the calls to a unit added with a retry policy", func(t *testing.T) {
+		flaky := unit_test.NewUnitMock()
+		flaky.On("Save", "query", "value", mock.Anything).Return(fmt.Errorf("transient")).Once()
+		flaky.On("Save", "query", "value", mock.Anything).Return(nil).Once()
+
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{}, nil)
+		assert.NoError(t, orchestrator.AddUnit("flaky", flaky, protocols.WithUnitRetry(protocols.RetryPolicy{MaxAttempts: 2})))
+		assert.NoError(t, orchestrator.SetStandardOrder("flaky"))
+
+		saved, err := orchestrator.Save("query", "value")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"flaky"}, saved)
+		flaky.AssertExpectations(t)
+	})
+
+	t.Run("should prefer the retry policy of the operation", func(t *testing.T) {
+		flaky := unit_test.NewUnitMock()
+		flaky.On("Delete", "query", mock.Anything).Return(fmt.Errorf("transient"))
+
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{}, nil)
+		assert.NoError(t, orchestrator.AddUnit("flaky", flaky, protocols.WithUnitRetry(protocols.RetryPolicy{MaxAttempts: 5})))
+		assert.NoError(t, orchestrator.SetStandardOrder("flaky"))
+
+		_, err := orchestrator.Delete("query", protocols.WithDeleteRetry(protocols.RetryPolicy{MaxAttempts: 2}))
+		assert.ErrorContains(t, err, "transient")
+		flaky.AssertNumberOfCalls(t, "Delete", 2)
+	})
+
+	t.Run("should hand the units untouched to the strategy when no policy is set", func(t *testing.T) {
+		setupOrchestrator()
+		getStrategy.On("Get", mock.Anything, "query", mock.Anything, []string{"mock1", "mock2"}, mock.Anything).Return("value", nil)
+
+		_, err := orchestrator.Get("query")
+		assert.NoError(t, err)
+
+		units := getStrategy.Calls[0].Arguments.Get(2).(map[string]protocols.StorageUnit[string, string])
+		assert.Same(t, mock1, units["mock1"])
+	})
+}
+
+func TestOrchestratorTimeout(t *testing.T) {
+
+	t.Run("should report which unit ran out of its timeout", func(t *testing.T) {
+		slow := unit_test.NewUnitMock()
+		slow.On("Get", "query", mock.Anything).Run(func(args mock.Arguments) {
+			<-args.Get(1).(context.Context).Done()
+		}).Return("", context.DeadlineExceeded)
+		fast := unit_test.NewMemoryUnit[string, string]()
+
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{}, nil)
+		assert.NoError(t, orchestrator.AddUnit("slow", slow, protocols.WithUnitTimeout(5*time.Millisecond)))
+		assert.NoError(t, orchestrator.AddUnit("fast", fast, protocols.WithUnitTimeout(time.Second)))
+		assert.NoError(t, orchestrator.SetStandardOrder("slow", "fast"))
+
+		_, err := orchestrator.Get("query")
+
+		var timeout *protocols.UnitTimeoutError
+		assert.ErrorAs(t, err, &timeout)
+		assert.Equal(t, "slow", timeout.Unit)
+	})
+
+	t.Run("should stop the operation at its deadline", func(t *testing.T) {
+		slow := unit_test.NewUnitMock()
+		slow.On("Save", "query", "value", mock.Anything).Run(func(args mock.Arguments) {
+			<-args.Get(2).(context.Context).Done()
+		}).Return(context.DeadlineExceeded)
+
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"slow": slow}, []string{"slow"})
+
+		start := time.Now()
+		_, err := orchestrator.Save("query", "value", protocols.WithSaveTimeout(5*time.Millisecond))
+
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+}
+
+func TestOrchestratorInterceptors(t *testing.T) {
+
+	t.Run("should intercept every unit call, including cache backfills", func(t *testing.T) {
+		cache := unit_test.NewMemoryUnit[string, string]()
+		primary := unit_test.NewMemoryUnit[string, string]()
+		primary.Save(context.Background(), "query", "value")
+
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{}, nil)
+		assert.NoError(t, orchestrator.AddUnit("cache", cache))
+		assert.NoError(t, orchestrator.AddUnit("primary", primary))
+		assert.NoError(t, orchestrator.SetStandardOrder("cache", "primary"))
+
+		var mu sync.Mutex
+		var calls []string
+		orchestrator.Use(func(next protocols.UnitCallFunc[string, string]) protocols.UnitCallFunc[string, string] {
+			return func(ctx context.Context, call *protocols.UnitCall[string, string]) error {
+				err := next(ctx, call)
+				mu.Lock()
+				defer mu.Unlock()
+				calls = append(calls, fmt.Sprintf("%v %v %v", call.Operation, call.Unit, call.Query))
+				return err
+			}
+		})
+
+		value, err := orchestrator.Get("query")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+		assert.Equal(t, []string{"get cache query", "get primary query", "save cache query"}, calls)
+	})
+
+	t.Run("should run the interceptors on every retry attempt", func(t *testing.T) {
+		flaky := unit_test.NewUnitMock()
+		flaky.On("Delete", "query", mock.Anything).Return(fmt.Errorf("transient")).Once()
+		flaky.On("Delete", "query", mock.Anything).Return(nil).Once()
+
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{}, nil)
+		assert.NoError(t, orchestrator.AddUnit("flaky", flaky, protocols.WithUnitRetry(protocols.RetryPolicy{MaxAttempts: 2})))
+		assert.NoError(t, orchestrator.SetStandardOrder("flaky"))
+
+		var outcomes []error
+		orchestrator.Use(func(next protocols.UnitCallFunc[string, string]) protocols.UnitCallFunc[string, string] {
+			return func(ctx context.Context, call *protocols.UnitCall[string, string]) error {
+				err := next(ctx, call)
+				outcomes = append(outcomes, err)
+				return err
+			}
+		})
+
+		_, err := orchestrator.Delete("query")
+		assert.NoError(t, err)
+		assert.Len(t, outcomes, 2)
+		assert.EqualError(t, outcomes[0], "transient")
+		assert.NoError(t, outcomes[1])
+	})
+}
+
+func TestOrchestratorLogging(t *testing.T) {
+
+	t.Run("should log hashed keys and the backfills that failed", func(t *testing.T) {
+		cache := unit_test.NewUnitMock()
+		cache.On("Get", "secret@example.com", mock.Anything).Return("", protocols.ErrNotFound)
+		cache.On("Save", "secret@example.com", "value", mock.Anything).Return(fmt.Errorf("cache down"))
+		primary := unit_test.NewMemoryUnit[string, string]()
+		primary.Save(context.Background(), "secret@example.com", "value")
+
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{}, nil)
+		orchestrator.AddUnit("cache", cache)
+		orchestrator.AddUnit("primary", primary)
+		orchestrator.SetStandardOrder("cache", "primary")
+
+		var logs bytes.Buffer
+		orchestrator.SetLogger(slog.New(slog.NewJSONHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})))
+
+		value, err := orchestrator.Get("secret@example.com")
+		assert.Equal(t, "value", value)
+		assert.Error(t, err)
+
+		assert.NotContains(t, logs.String(), "secret@example.com")
+
+		var records []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+			var record map[string]any
+			assert.NoError(t, json.Unmarshal([]byte(line), &record))
+			records = append(records, record)
+		}
+
+		var backfill, operation map[string]any
+		for _, record := range records {
+			assert.Equal(t, protocols.HashKey("secret@example.com"), record["key"])
+			switch record["msg"] {
+			case "backfill failed":
+				backfill = record
+			case "operation":
+				operation = record
+			}
+		}
+		assert.Len(t, records, 5)
+		assert.Equal(t, "WARN", backfill["level"])
+		assert.Equal(t, "cache", backfill["unit"])
+		assert.Equal(t, "cache down", backfill["error"])
+		assert.Equal(t, "ERROR", operation["level"])
+		assert.Equal(t, "get", operation["operation"])
+		assert.Equal(t, "cache", operation["strategy"])
+		assert.Contains(t, operation, "duration")
+	})
+
+	t.Run("should log keys through the given redactor", func(t *testing.T) {
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"memory": unit_test.NewMemoryUnit[string, string]()}, []string{"memory"})
+
+		var logs bytes.Buffer
+		orchestrator.SetLogger(slog.New(slog.NewTextHandler(&logs, &slog.HandlerOptions{Level: slog.LevelDebug})),
+			protocols.WithKeyRedactor(func(key string) string { return "user:***" }))
+
+		_, err := orchestrator.Save("user:42", "value")
+		assert.NoError(t, err)
+		assert.Contains(t, logs.String(), "key=user:***")
+		assert.NotContains(t, logs.String(), "user:42")
+	})
+
+	t.Run("should hash keys with a secret instead of a plain digest", func(t *testing.T) {
+		plain := sha256.Sum256([]byte("user:42"))
+		assert.NotEqual(t, hex.EncodeToString(plain[:8]), protocols.HashKey("user:42"))
+
+		first := protocols.HMACKey[string]([]byte("secret"))
+		second := protocols.HMACKey[string]([]byte("secret"))
+		other := protocols.HMACKey[string]([]byte("other secret"))
+		assert.Equal(t, first("user:42"), second("user:42"))
+		assert.NotEqual(t, first("user:42"), other("user:42"))
+		assert.Len(t, first("user:42"), 16)
+	})
+}
+
+var coalescingCache *unit_test.UnitMock
+var coalescingPrimary *unit_test.UnitMock
+var coalescingLooking chan struct{}
+var coalescingRelease chan struct{}
+var coalescingOrchestrator *Orchestrator[string, string]
+
+func coalescingSetup(coalesce bool) {
+	coalescingCache = unit_test.NewUnitMock()
+	coalescingCache.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+	coalescingCache.On("Save", "query", "value", mock.Anything).Return(nil)
+
+	looking := make(chan struct{}, 100)
+	release := make(chan struct{})
+	coalescingLooking, coalescingRelease = looking, release
+	coalescingPrimary = unit_test.NewUnitMock()
+	coalescingPrimary.On("Get", "query", mock.Anything).Run(func(mock.Arguments) {
+		looking <- struct{}{}
+		<-release
+	}).Return("value", nil)
+
+	orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"cache": coalescingCache, "primary": coalescingPrimary}, []string{"cache", "primary"})
+	if coalesce {
+		orchestrator.SetCoalescing(func(key string) string { return key })
+	}
+	coalescingOrchestrator = &orchestrator
+}
+
+// getConcurrently runs 10 gets and lets the lookups finish once each get is
+// either looking the key up or waiting on the one that is.
+func getConcurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for c := 0; c < 10; c++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := coalescingOrchestrator.Get("query")
+			assert.NoError(t, err)
+			assert.Equal(t, "value", value)
+		}()
+	}
+
+	lookups := 0
+	for lookups+coalescingOrchestrator.flights.waiting() < 10 {
+		select {
+		case <-coalescingLooking:
+			lookups++
+		case <-time.After(time.Millisecond):
+		}
+	}
+	close(coalescingRelease)
+	wg.Wait()
+}
+
+func TestOrchestratorCoalescing(t *testing.T) {
+
+	t.Run("should share one lookup and one backfill between concurrent gets", func(t *testing.T) {
+		coalescingSetup(true)
+
+		getConcurrently(t)
+
+		coalescingPrimary.AssertNumberOfCalls(t, "Get", 1)
+		coalescingCache.AssertNumberOfCalls(t, "Save", 1)
+	})
+
+	t.Run("should look every get up when coalescing is off", func(t *testing.T) {
+		coalescingSetup(false)
+
+		getConcurrently(t)
+
+		coalescingPrimary.AssertNumberOfCalls(t, "Get", 10)
+		coalescingCache.AssertNumberOfCalls(t, "Save", 10)
+	})
+
+	t.Run("should not share a lookup between gets with different options", func(t *testing.T) {
+		coalescingSetup(true)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			coalescingOrchestrator.Get("query")
+		}()
+		<-coalescingLooking
+
+		go coalescingOrchestrator.Get("query", protocols.WithGetTimeout(time.Minute))
+		<-coalescingLooking
+		close(coalescingRelease)
+		<-done
+
+		assert.Equal(t, 0, coalescingOrchestrator.flights.waiting())
+	})
+
+	t.Run("should stop waiting at the deadline of the caller", func(t *testing.T) {
+		coalescingSetup(true)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			coalescingOrchestrator.Get("query")
+		}()
+		<-coalescingLooking
+
+		ctx, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+		defer cancel()
+		_, err := coalescingOrchestrator.Get("query", func(opt *protocols.GetOptions) { opt.Context = ctx })
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		close(coalescingRelease)
+		<-done
+	})
+
+	t.Run("should fail the waiting calls when the shared call panics", func(t *testing.T) {
+		var flights flightGroup[string]
+		started := make(chan struct{})
+		release := make(chan struct{})
+
+		panicked := make(chan any)
+		go func() {
+			defer func() { panicked <- recover() }()
+			flights.do(context.Background(), "key", func() (string, error) {
+				close(started)
+				<-release
+				panic("boom")
+			})
+		}()
+		<-started
+
+		waited := make(chan error)
+		go func() {
+			_, err := flights.do(context.Background(), "key", func() (string, error) { return "value", nil })
+			waited <- err
+		}()
+		for flights.waiting() < 1 {
+			time.Sleep(time.Millisecond)
+		}
+		close(release)
+
+		assert.Equal(t, "boom", <-panicked)
+		assert.ErrorContains(t, <-waited, "panicked: boom")
+	})
+}
+
+func TestOrchestratorStaleWhileRevalidate(t *testing.T) {
+
+	t.Run("should read the metadata through the unit decorators", func(t *testing.T) {
+		cache := unit_test.NewMemoryUnit[string, string]()
+		source := unit_test.NewMemoryUnit[string, string]()
+		cache.SaveWithMetadata(context.Background(), "query", "old", protocols.Metadata{Stale: true})
+		source.Save(context.Background(), "query", "new")
+
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{}, nil)
+		orchestrator.AddUnit("cache", cache, protocols.WithUnitRetry(protocols.RetryPolicy{MaxAttempts: 2}), protocols.WithUnitTimeout(time.Second))
+		orchestrator.AddUnit("source", source)
+		orchestrator.SetStandardOrder("cache", "source")
+		orchestrator.Use(func(next protocols.UnitCallFunc[string, string]) protocols.UnitCallFunc[string, string] {
+			return next
+		})
+
+		revalidated := make(chan error, 1)
+		orchestrator.RegisterGetStrategy(protocols.StaleWhileRevalidate, &strategies.StaleWhileRevalidateGetStrategy[string, string]{
+			OnRevalidate: func(query string, err error) {
+				revalidated <- err
+			},
+		})
+
+		value, err := orchestrator.Get("query", protocols.WithGetStrategy(protocols.StaleWhileRevalidate))
+		assert.NoError(t, err)
+		assert.Equal(t, "old", value)
+
+		assert.NoError(t, <-revalidated)
+		value, err = orchestrator.Get("query", protocols.WithGetStrategy(protocols.StaleWhileRevalidate))
+		assert.NoError(t, err)
+		assert.Equal(t, "new", value)
+	})
+}
+
+var negativeCacheUnit *unit_test.UnitMock
+var negativeCachePrimary *unit_test.UnitMock
+var negativeCacheOrchestrator *Orchestrator[string, string]
+
+func negativeCacheSetup(ttl time.Duration) {
+	negativeCacheUnit = unit_test.NewUnitMock()
+	negativeCachePrimary = unit_test.NewUnitMock()
+	orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"cache": negativeCacheUnit, "primary": negativeCachePrimary}, []string{"cache", "primary"})
+	orchestrator.SetNegativeCache(ttl, func(key string) string { return key })
+	negativeCacheOrchestrator = &orchestrator
+}
+
+func TestOrchestratorNegativeCache(t *testing.T) {
+
+	t.Run("should answer a confirmed miss without calling the units until it expires", func(t *testing.T) {
+		negativeCacheSetup(20 * time.Millisecond)
+		negativeCacheUnit.On("Get", "missing", mock.Anything).Return("", protocols.ErrNotFound)
+		negativeCachePrimary.On("Get", "missing", mock.Anything).Return("", protocols.ErrNotFound)
+
+		_, err := negativeCacheOrchestrator.Get("missing")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+		_, err = negativeCacheOrchestrator.Get("missing")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+		_, err = negativeCacheOrchestrator.Get("missing", func(opt *protocols.GetOptions) {
+			opt.Targets = []string{"primary"}
+		})
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+		negativeCachePrimary.AssertNumberOfCalls(t, "Get", 1)
+
+		time.Sleep(30 * time.Millisecond)
+		negativeCacheOrchestrator.Get("missing")
+		negativeCachePrimary.AssertNumberOfCalls(t, "Get", 2)
+	})
+
+	t.Run("should forget the miss when the key is saved", func(t *testing.T) {
+		negativeCacheSetup(time.Minute)
+		negativeCacheUnit.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound).Once()
+		negativeCachePrimary.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound).Once()
+		negativeCacheUnit.On("Save", "query", "value", mock.Anything).Return(nil)
+		negativeCachePrimary.On("Save", "query", "value", mock.Anything).Return(nil)
+		negativeCacheUnit.On("Get", "query", mock.Anything).Return("value", nil)
+
+		negativeCacheOrchestrator.Get("query")
+		_, err := negativeCacheOrchestrator.Save("query", "value")
+		assert.NoError(t, err)
+
+		value, err := negativeCacheOrchestrator.Get("query")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+
+	t.Run("should forget the miss again once the writes left running by a quorum save settled", func(t *testing.T) {
+		negativeCacheSetup(time.Minute)
+		release := make(chan struct{})
+		negativeCacheUnit.On("Save", "query", "value", mock.Anything).Return(nil)
+		negativeCachePrimary.On("Save", "query", "value", mock.Anything).Run(func(mock.Arguments) {
+			<-release
+		}).Return(nil)
+		negativeCachePrimary.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound).Once()
+		negativeCachePrimary.On("Get", "query", mock.Anything).Return("value", nil)
+		primaryOnly := func(opt *protocols.GetOptions) {
+			opt.Targets = []string{"primary"}
+		}
+
+		_, err := negativeCacheOrchestrator.Save("query", "value", protocols.WithWriteQuorum(1))
+		assert.NoError(t, err)
+		_, err = negativeCacheOrchestrator.Get("query", primaryOnly)
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+
+		close(release)
+		assert.Eventually(t, func() bool {
+			value, err := negativeCacheOrchestrator.Get("query", primaryOnly)
+			return err == nil && value == "value"
+		}, time.Second, time.Millisecond)
+	})
+
+	t.Run("should not cache a miss when a unit failed or was not asked", func(t *testing.T) {
+		negativeCacheSetup(time.Minute)
+		negativeCacheUnit.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		negativeCachePrimary.On("Get", "query", mock.Anything).Return("", fmt.Errorf("down")).Once()
+		negativeCachePrimary.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+
+		negativeCacheOrchestrator.Get("query")
+		negativeCacheOrchestrator.Get("query", func(opt *protocols.GetOptions) {
+			opt.Targets = []string{"cache"}
+		})
+		negativeCacheOrchestrator.Get("query")
+		negativeCachePrimary.AssertNumberOfCalls(t, "Get", 2)
+	})
+
+	t.Run("should report the misses it answers to the observers", func(t *testing.T) {
+		negativeCacheSetup(time.Minute)
+		negativeCacheUnit.On("Get", "missing", mock.Anything).Return("", protocols.ErrNotFound)
+		negativeCachePrimary.On("Get", "missing", mock.Anything).Return("", protocols.ErrNotFound)
+		var operations []protocols.OperationInfo
+		negativeCacheOrchestrator.Observe(func(info protocols.OperationInfo) {
+			operations = append(operations, info)
+		})
+
+		negativeCacheOrchestrator.Get("missing")
+		negativeCacheOrchestrator.Get("missing")
+
+		negativeCachePrimary.AssertNumberOfCalls(t, "Get", 1)
+		assert.Len(t, operations, 2)
+		assert.Equal(t, protocols.GetOperation, operations[1].Operation)
+		assert.ErrorContains(t, operations[1].Err, "negative cache")
+	})
+
+	t.Run("should record a miss while other keys are saved", func(t *testing.T) {
+		negativeCacheSetup(time.Minute)
+		looking := make(chan struct{})
+		release := make(chan struct{})
+		negativeCacheUnit.On("Get", "missing", mock.Anything).Return("", protocols.ErrNotFound)
+		negativeCachePrimary.On("Get", "missing", mock.Anything).Run(func(mock.Arguments) {
+			close(looking)
+			<-release
+		}).Return("", protocols.ErrNotFound).Once()
+		negativeCacheUnit.On("Save", "other", "value", mock.Anything).Return(nil)
+		negativeCachePrimary.On("Save", "other", "value", mock.Anything).Return(nil)
+
+		done := make(chan struct{})
+		go func() {
+			defer close(done)
+			negativeCacheOrchestrator.Get("missing")
+		}()
+		<-looking
+		_, err := negativeCacheOrchestrator.Save("other", "value")
+		assert.NoError(t, err)
+		close(release)
+		<-done
+
+		_, err = negativeCacheOrchestrator.Get("missing")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+		negativeCachePrimary.AssertNumberOfCalls(t, "Get", 1)
+	})
+}
+
+func TestOrchestratorWriteAround(t *testing.T) {
+	ctx := context.Background()
+	cache := unit_test.NewMemoryUnit[string, string]()
+	replica := unit_test.NewMemoryUnit[string, string]()
+	orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"cache": cache}, []string{"cache"})
+	assert.NoError(t, orchestrator.AddUnit("database", unit_test.NewMemoryUnit[string, string](), protocols.WithUnitRole(protocols.PrimaryRole)))
+	assert.NoError(t, orchestrator.AddUnit("replica", replica, protocols.WithUnitRole(protocols.ReplicaRole)))
+	assert.NoError(t, orchestrator.SetStandardOrder("cache", "database", "replica"))
+	cache.Save(ctx, "query", "old")
+	replica.Save(ctx, "query", "old")
+
+	saved, err := orchestrator.Save("query", "new", protocols.WithSaveStrategy(protocols.WriteAround))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"database"}, saved)
+	_, err = cache.Get(ctx, "query")
+	assert.ErrorIs(t, err, protocols.ErrNotFound)
+	value, _ := replica.Get(ctx, "query")
+	assert.Equal(t, "old", value)
+
+	value, err = orchestrator.Get("query")
+	assert.NoError(t, err)
+	assert.Equal(t, "new", value)
+	value, _ = cache.Get(ctx, "query")
+	assert.Equal(t, "new", value)
+
+	saved, err = orchestrator.Save("query", "newer", protocols.WithSaveStrategy(protocols.SourceOnly))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"database"}, saved)
+	value, _ = cache.Get(ctx, "query")
+	assert.Equal(t, "new", value)
+}
+
+func TestOrchestratorWriteBehind(t *testing.T) {
+
+	t.Run("should flush through the decorated units", func(t *testing.T) {
+		ctx := context.Background()
+		cache := unit_test.NewMemoryUnit[string, string]()
+		database := unit_test.NewMemoryUnit[string, string]()
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"cache": cache, "database": database}, []string{"cache", "database"})
+
+		var mu sync.Mutex
+		var calls []string
+		orchestrator.Use(func(next protocols.UnitCallFunc[string, string]) protocols.UnitCallFunc[string, string] {
+			return func(ctx context.Context, call *protocols.UnitCall[string, string]) error {
+				mu.Lock()
+				calls = append(calls, fmt.Sprintf("%v %v", call.Operation, call.Unit))
+				mu.Unlock()
+				return next(ctx, call)
+			}
+		})
+
+		writeBehind := strategies.NewWriteBehindSaveStrategy[string, string](queues.NewMemoryQueue[string, string](), orchestrator.DecoratedUnits(string(protocols.WriteBehind)), strategies.WriteBehindConfig[string, string]{FlushInterval: time.Hour})
+		assert.NoError(t, orchestrator.RegisterSaveStrategy(protocols.WriteBehind, writeBehind))
+
+		saved, err := orchestrator.Save("query", "value", protocols.WithSaveStrategy(protocols.WriteBehind))
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cache"}, saved)
+		assert.NoError(t, writeBehind.Close(ctx))
+
+		value, _ := database.Get(ctx, "query")
+		assert.Equal(t, "value", value)
+		assert.Equal(t, []string{"save cache", "save database"}, calls)
+	})
+
+	t.Run("should forget the misses of the keys it flushed", func(t *testing.T) {
+		ctx := context.Background()
+		release := make(chan struct{})
+		database := unit_test.NewUnitMock()
+		database.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound).Once()
+		database.On("Get", "query", mock.Anything).Return("value", nil)
+		database.On("Save", "query", "value", mock.Anything).Run(func(mock.Arguments) {
+			<-release
+		}).Return(nil)
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"cache": unit_test.NewMemoryUnit[string, string](), "database": database}, []string{"cache", "database"})
+		orchestrator.SetNegativeCache(time.Minute, func(key string) string { return key })
+
+		writeBehind := strategies.NewWriteBehindSaveStrategy[string, string](queues.NewMemoryQueue[string, string](), orchestrator.DecoratedUnits(string(protocols.WriteBehind)), strategies.WriteBehindConfig[string, string]{FlushInterval: time.Hour})
+		assert.NoError(t, orchestrator.RegisterSaveStrategy(protocols.WriteBehind, writeBehind))
+		onlyDatabase := func(opt *protocols.GetOptions) { opt.Targets = []string{"database"} }
+
+		_, err := orchestrator.Save("query", "value", protocols.WithSaveStrategy(protocols.WriteBehind))
+		assert.NoError(t, err)
+		_, err = orchestrator.Get("query", onlyDatabase)
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+
+		close(release)
+		assert.NoError(t, writeBehind.Close(ctx))
+		value, err := orchestrator.Get("query", onlyDatabase)
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+}
+
+func TestOrchestratorAtomicSave(t *testing.T) {
+	ctx := context.Background()
+	cache := unit_test.NewMemoryUnit[string, string]()
+	database := unit_test.NewUnitMock()
+	database.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+	database.On("Save", "query", "value", mock.Anything).Return(fmt.Errorf("database down"))
+	orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"cache": cache, "database": database}, []string{"cache", "database"})
+
+	for _, strategy := range []protocols.TypeSaveOptions{protocols.Atomic, protocols.AtomicParallel} {
+		saved, err := orchestrator.Save("query", "value", protocols.WithSaveStrategy(strategy))
+		assert.ErrorContains(t, err, "database down")
+		assert.Empty(t, saved)
+		_, err = cache.Get(ctx, "query")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+	}
+}
+
+func TestOrchestratorTwoPhaseSave(t *testing.T) {
+	ctx := context.Background()
+	first := unit_test.NewTransactionalMemoryUnit[string, string]()
+	second := unit_test.NewTransactionalMemoryUnit[string, string]()
+	orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"first": first}, nil)
+	assert.NoError(t, orchestrator.AddUnit("second", second, protocols.WithUnitTimeout(time.Second), protocols.WithUnitRetry(protocols.RetryPolicy{MaxAttempts: 2})))
+	assert.NoError(t, orchestrator.AddUnit("plain", unit_test.NewMemoryUnit[string, string]()))
+	assert.NoError(t, orchestrator.SetStandardOrder("first", "second"))
+
+	var operations []protocols.UnitOperation
+	var mu sync.Mutex
+	orchestrator.Use(func(next protocols.UnitCallFunc[string, string]) protocols.UnitCallFunc[string, string] {
+		return func(ctx context.Context, call *protocols.UnitCall[string, string]) error {
+			if call.Unit == "second" {
+				mu.Lock()
+				operations = append(operations, call.Operation)
+				mu.Unlock()
+			}
+			return next(ctx, call)
+		}
+	})
+
+	saved, err := orchestrator.Save("query", "value", protocols.WithSaveStrategy(protocols.TwoPhase))
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"first", "second"}, saved)
+	value, _ := second.Get(ctx, "query")
+	assert.Equal(t, "value", value)
+	assert.Equal(t, []protocols.UnitOperation{protocols.PrepareOperation, protocols.CommitOperation}, operations)
+
+	_, err = orchestrator.Save("query", "value", protocols.WithSaveStrategy(protocols.TwoPhase), func(opt *protocols.SaveOptions) {
+		opt.Targets = []string{"first", "plain"}
+	})
+	assert.ErrorIs(t, err, protocols.ErrTransactionsUnsupported)
+}
+
+func TestOrchestratorUnitInfo(t *testing.T) {
+
+	t.Run("should return the options a unit was added with", func(t *testing.T) {
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"database": unit_test.NewMemoryUnit[string, string]()}, []string{"database"})
+		assert.NoError(t, orchestrator.AddUnit("archive", unit_test.NewMemoryUnit[string, string](),
+			protocols.WithUnitRole(protocols.ArchiveRole), protocols.WithUnitDurability(protocols.Durable),
+			protocols.WithUnitReadOnly(), protocols.WithUnitPriority(2), protocols.WithUnitTags("cold", "s3")))
+
+		info, err := orchestrator.GetUnitInfo("archive")
+		assert.NoError(t, err)
+		assert.Equal(t, "archive", info.Name)
+		assert.Equal(t, protocols.ArchiveRole, info.Role)
+		assert.Equal(t, protocols.Durable, info.Durability)
+		assert.True(t, info.ReadOnly)
+		assert.Equal(t, 2, info.Priority)
+		assert.Equal(t, []string{"cold", "s3"}, info.Tags)
+
+		info, err = orchestrator.GetUnitInfo("database")
+		assert.NoError(t, err)
+		assert.Equal(t, protocols.UnitInfo{Name: "database"}, info)
+
+		_, err = orchestrator.GetUnitInfo("missing")
+		assert.ErrorIs(t, err, protocols.ErrUnitNotFound)
+	})
+
+	t.Run("should never write to a read-only unit", func(t *testing.T) {
+		ctx := context.Background()
+		cache := unit_test.NewMemoryUnit[string, string]()
+		archive := unit_test.NewMemoryUnit[string, string]()
+		archive.Save(ctx, "query", "archived")
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"cache": cache}, nil)
+		assert.NoError(t, orchestrator.AddUnit("archive", archive, protocols.WithUnitReadOnly()))
+		assert.NoError(t, orchestrator.SetStandardOrder("cache", "archive"))
+
+		value, err := orchestrator.Get("query")
+		assert.NoError(t, err)
+		assert.Equal(t, "archived", value)
+
+		saved, err := orchestrator.Save("query", "new")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cache"}, saved)
+		deleted, err := orchestrator.Delete("query")
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"cache"}, deleted)
+		value, _ = archive.Get(ctx, "query")
+		assert.Equal(t, "archived", value)
+
+		_, err = orchestrator.Save("query", "new", func(opt *protocols.SaveOptions) {
+			opt.Targets = []string{"archive"}
+		})
+		assert.ErrorIs(t, err, protocols.ErrReadOnlyUnit)
+	})
+
+	t.Run("should not remember a miss only caches confirmed", func(t *testing.T) {
+		cache := unit_test.NewUnitMock()
+		database := unit_test.NewUnitMock()
+		orchestrator := NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"database": database}, nil)
+		assert.NoError(t, orchestrator.AddUnit("cache", cache, protocols.WithUnitRole(protocols.CacheRole)))
+		assert.NoError(t, orchestrator.SetStandardOrder("cache", "database"))
+		orchestrator.SetNegativeCache(time.Minute, func(key string) string { return key })
+		cache.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		database.On("Get", "query", mock.Anything).Return("value", nil)
+		cache.On("Save", "query", "value", mock.Anything).Return(nil)
+
+		onlyCache := func(opt *protocols.GetOptions) {
+			opt.Targets = []string{"cache"}
+		}
+		_, err := orchestrator.Get("query", onlyCache)
+		assert.ErrorIs(t, err, protocols.ErrUnconfirmedMiss)
+		_, err = orchestrator.Get("query", onlyCache)
+		assert.ErrorIs(t, err, protocols.ErrUnconfirmedMiss)
+		cache.AssertNumberOfCalls(t, "Get", 2)
+
+		value, err := orchestrator.Get("query")
+		assert.NoError(t, err)
+		assert.Equal(t, "value", value)
+	})
+}
+
+func TestOrchestratorBatch(t *testing.T) {
+
+	t.Run("should save, get and delete many keys across the units", func(t *testing.T) {
+		memory1 := unit_test.NewMemoryUnit[string, string]()
+		memory2 := unit_test.NewMemoryUnit[string, string]()
+		units := map[string]protocols.StorageUnit[string, string]{"memory1": memory1, "memory2": memory2}
+		orchestrator := NewOrchestrator[string, string](units, []string{"memory1", "memory2"})
+
+		saved, err := orchestrator.SaveMany([]string{"a", "b"}, []string{"va", "vb"}, func(opt *protocols.SaveOptions) {
+			opt.Targets = []string{"memory2"}
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"memory2"}, saved)
+
+		values, err := orchestrator.GetMany([]string{"a", "b", "c"})
+		assert.Equal(t, []string{"va", "vb", ""}, values)
+
+		var batchErrs protocols.BatchErrors
+		assert.ErrorAs(t, err, &batchErrs)
+		assert.NoError(t, batchErrs[0])
+		assert.NoError(t, batchErrs[1])
+		assert.ErrorIs(t, batchErrs[2], protocols.ErrNotFound)
+
+		backfilled, err := memory1.Get(context.Background(), "b")
+		assert.NoError(t, err)
+		assert.Equal(t, "vb", backfilled)
+
+		deleted, err := orchestrator.DeleteMany([]string{"a", "b"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"memory1", "memory2"}, deleted)
+
+		_, err = memory2.Get(context.Background(), "a")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+	})
+
+	t.Run("should return error when the strategy does not support batches", func(t *testing.T) {
+		setupOrchestrator()
+
+		_, err := orchestrator.SaveMany([]string{"a"}, []string{"va"})
+		assert.ErrorIs(t, err, protocols.ErrBatchUnsupported)
+		assert.NotErrorIs(t, err, protocols.ErrUnknownStrategy)
+	})
+
+	t.Run("should return error when the queries and items do not match", func(t *testing.T) {
+		setupOrchestrator()
+
+		_, err := orchestrator.SaveMany([]string{"a", "b"}, []string{"va"})
+		assert.ErrorContains(t, err, "got 2 queries for 1 items")
+	})
+}
+
+func TestOrchestratorConcurrency(t *testing.T) {
+
+	t.Run("should not race when units and order change while operations run", func(t *testing.T) {
+		units := map[string]protocols.StorageUnit[string, string]{
+			"memory1": unit_test.NewMemoryUnit[string, string](),
+			"memory2": unit_test.NewMemoryUnit[string, string](),
+		}
+		orchestrator := NewOrchestrator[string, string](units, []string{"memory1", "memory2"})
+
+		var wg sync.WaitGroup
+		for worker := 0; worker < 4; worker++ {
+			wg.Add(1)
+			go func(worker int) {
+				defer wg.Done()
+				for c := 0; c < 50; c++ {
+					key := fmt.Sprintf("key-%v-%v", worker, c)
+					orchestrator.Save(key, "value", protocols.WithSaveStrategy(protocols.Parallel))
+					orchestrator.Get(key)
+					orchestrator.Get(key, protocols.WithGetStrategy(protocols.Race))
+					orchestrator.Delete(key, protocols.WithDeleteStrategy(protocols.ParallelDelete))
+				}
+			}(worker)
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for c := 0; c < 50; c++ {
+				orchestrator.AddUnit("memory3", unit_test.NewMemoryUnit[string, string]())
+				orchestrator.SetStandardOrder("memory3", "memory1", "memory2")
+				orchestrator.ReplaceUnit("memory1", unit_test.NewMemoryUnit[string, string]())
+				orchestrator.RemoveUnit("memory3")
+				orchestrator.GetUnits()
+			}
+		}()
+
+		wg.Wait()
+
+		units, err := orchestrator.GetUnits()
+		assert.NoError(t, err)
+		assert.Len(t, units, 2)
+		assert.Equal(t, []string{"memory1", "memory2"}, orchestrator.standardOrder)
+	})
+
+	t.Run("should return error instead of panicking when a target was removed", func(t *testing.T) {
+		setupOrchestrator()
+		assert.NoError(t, orchestrator.RemoveUnit("mock2"))
+
+		_, err := orchestrator.Save("query", "value", func(opt *protocols.SaveOptions) {
+			opt.Targets = []string{"mock1", "mock2"}
+		})
+		assert.ErrorIs(t, err, protocols.ErrUnitNotFound)
+		saveStrategy.AssertNotCalled(t, "Save")
+	})
 }
@@ -0,0 +1,28 @@
+module github.com/joaogabriel01/storage-orchestrator/pkg/oteltracing
+
+go 1.21.6
+
+require (
+	github.com/joaogabriel01/storage-orchestrator v0.0.0-20261017131107-d19a2634d526
+	github.com/stretchr/testify v1.9.0
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+)
+
+require (
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/pmezard/go-difflib v1.0.0 // indirect
+	github.com/stretchr/objx v0.5.2 // indirect
+	go.opentelemetry.io/otel/metric v1.28.0 // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// Builds inside this repository use the root module next to the adapter;
+// dependents resolve the version required above, since Go ignores the replace
+// directives of dependencies.
+replace github.com/joaogabriel01/storage-orchestrator => ../..
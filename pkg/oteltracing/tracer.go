@@ -0,0 +1,68 @@
+// Package oteltracing adapts an OpenTelemetry tracer to protocols.Tracer.
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type Tracer struct {
+	Tracer trace.Tracer
+}
+
+// NewTracer returns a protocols.Tracer that starts its spans with tracer, for
+// example otel.Tracer("storage-orchestrator").
+func NewTracer(tracer trace.Tracer) *Tracer {
+	return &Tracer{Tracer: tracer}
+}
+
+func (t *Tracer) Start(ctx context.Context, name string, attributes ...protocols.Attribute) (context.Context, protocols.Span) {
+	ctx, span := t.Tracer.Start(ctx, name, trace.WithAttributes(convert(attributes)...))
+	return ctx, &Span{Span: span}
+}
+
+type Span struct {
+	Span trace.Span
+}
+
+func (s *Span) SetAttributes(attributes ...protocols.Attribute) {
+	s.Span.SetAttributes(convert(attributes)...)
+}
+
+func (s *Span) RecordError(err error) {
+	s.Span.RecordError(err)
+	s.Span.SetStatus(codes.Error, err.Error())
+}
+
+func (s *Span) End() {
+	s.Span.End()
+}
+
+func convert(attributes []protocols.Attribute) []attribute.KeyValue {
+	converted := make([]attribute.KeyValue, len(attributes))
+	for c, attr := range attributes {
+		switch value := attr.Value.(type) {
+		case string:
+			converted[c] = attribute.String(attr.Key, value)
+		case bool:
+			converted[c] = attribute.Bool(attr.Key, value)
+		case int:
+			converted[c] = attribute.Int(attr.Key, value)
+		case int64:
+			converted[c] = attribute.Int64(attr.Key, value)
+		case float64:
+			converted[c] = attribute.Float64(attr.Key, value)
+		default:
+			converted[c] = attribute.String(attr.Key, fmt.Sprint(value))
+		}
+	}
+	return converted
+}
+
+var _ protocols.Tracer = (*Tracer)(nil)
+var _ protocols.Span = (*Span)(nil)
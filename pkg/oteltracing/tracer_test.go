@@ -0,0 +1,87 @@
+package oteltracing
+
+import (
+	"context"
+	"fmt"
+	"testing"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg"
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func setupTracing() (*tracetest.InMemoryExporter, *Tracer) {
+	exporter := tracetest.NewInMemoryExporter()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	return exporter, NewTracer(provider.Tracer("test"))
+}
+
+func attributes(span tracetest.SpanStub) map[attribute.Key]attribute.Value {
+	values := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestTracer(t *testing.T) {
+
+	t.Run("should emit a span per operation with a child span per unit call", func(t *testing.T) {
+		exporter, tracer := setupTracing()
+		cache := unit_test.NewMemoryUnit[string, string]()
+		primary := unit_test.NewMemoryUnit[string, string]()
+		primary.Save(context.Background(), "query", "value")
+
+		orchestrator := pkg.NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{}, nil)
+		orchestrator.AddUnit("cache", cache)
+		orchestrator.AddUnit("primary", primary)
+		orchestrator.SetStandardOrder("cache", "primary")
+		orchestrator.SetTracer(tracer)
+
+		_, err := orchestrator.Get("query")
+		assert.NoError(t, err)
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 4)
+
+		parent := spans[len(spans)-1]
+		assert.Equal(t, "orchestrator.get", parent.Name)
+		assert.Equal(t, "cache", attributes(parent)[protocols.AttributeStrategy].AsString())
+		assert.Equal(t, protocols.OutcomeSuccess, attributes(parent)[protocols.AttributeOutcome].AsString())
+
+		var calls []string
+		for _, span := range spans[:len(spans)-1] {
+			assert.Equal(t, parent.SpanContext.SpanID(), span.Parent.SpanID())
+			attrs := attributes(span)
+			assert.Equal(t, "cache", attrs[protocols.AttributeStrategy].AsString())
+			calls = append(calls, fmt.Sprintf("%v %v %v", span.Name, attrs[protocols.AttributeUnit].AsString(), attrs[protocols.AttributeOutcome].AsString()))
+		}
+		assert.Equal(t, []string{"unit.get cache miss", "unit.get primary success", "unit.save cache success"}, calls)
+	})
+
+	t.Run("should mark the spans of failed calls as errors", func(t *testing.T) {
+		exporter, tracer := setupTracing()
+		broken := unit_test.NewUnitMock()
+		broken.On("Delete", "query", mock.Anything).Return(fmt.Errorf("down"))
+
+		orchestrator := pkg.NewOrchestrator[string, string](map[string]protocols.StorageUnit[string, string]{"broken": broken}, []string{"broken"})
+		orchestrator.SetTracer(tracer)
+
+		_, err := orchestrator.Delete("query")
+		assert.Error(t, err)
+
+		spans := exporter.GetSpans()
+		assert.Len(t, spans, 2)
+		for _, span := range spans {
+			assert.Equal(t, codes.Error, span.Status.Code)
+			assert.Equal(t, protocols.OutcomeError, attributes(span)[protocols.AttributeOutcome].AsString())
+		}
+		assert.Equal(t, "down", spans[0].Status.Description)
+	})
+}
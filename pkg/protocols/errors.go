@@ -0,0 +1,134 @@
+package protocols
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+var (
+	ErrNotFound         = errors.New("item not found")
+	ErrUnitNotFound     = errors.New("unit not found")
+	ErrUnitInUse        = errors.New("unit is in the standard order")
+	ErrNoTargets        = errors.New("no targets")
+	ErrUnknownStrategy  = errors.New("unknown strategy")
+	ErrQuorumNotReached = errors.New("quorum not reached")
+	ErrCircuitOpen      = errors.New("circuit breaker is open")
+
+	ErrMetadataUnsupported = errors.New("unit does not report metadata")
+	ErrReadOnlyUnit        = errors.New("unit is read-only")
+	ErrUnconfirmedMiss     = errors.New("item not found in any authoritative unit")
+	ErrRollbackFailed      = errors.New("rollback failed")
+
+	ErrTransactionsUnsupported = errors.New("unit does not support transactions")
+	ErrBatchUnsupported        = errors.New("strategy does not support batches")
+)
+
+// UnitTimeoutError is returned when a unit call exceeds the unit's timeout
+// while the operation itself still had time left. It matches
+// context.DeadlineExceeded with errors.Is.
+type UnitTimeoutError struct {
+	Unit    string
+	Timeout time.Duration
+	Err     error
+}
+
+func (e *UnitTimeoutError) Error() string {
+	return fmt.Sprintf("unit %v timed out after %v", e.Unit, e.Timeout)
+}
+
+func (e *UnitTimeoutError) Unwrap() []error {
+	return []error{context.DeadlineExceeded, e.Err}
+}
+
+// RollbackError is returned by a save that failed after writing to some units
+// and could not undo the write in all of them. Err is the error of the save and
+// Failed maps each unit that may still hold the new item to the error of its
+// rollback. It matches both ErrRollbackFailed and Err with errors.Is.
+type RollbackError struct {
+	Err    error
+	Failed UnitErrors
+}
+
+func (e *RollbackError) Error() string {
+	return fmt.Sprintf("%v; rollback failed: %v", e.Err, e.Failed)
+}
+
+func (e *RollbackError) Unwrap() []error {
+	return []error{ErrRollbackFailed, e.Err}
+}
+
+// UnitErrors maps the name of each failed unit to the error it returned.
+type UnitErrors map[string]error
+
+func (u UnitErrors) Error() string {
+	messages := make([]string, 0, len(u))
+	for _, name := range u.Units() {
+		messages = append(messages, fmt.Sprintf("%v: %v", name, u[name]))
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (u UnitErrors) Unwrap() []error {
+	errs := make([]error, 0, len(u))
+	for _, name := range u.Units() {
+		errs = append(errs, u[name])
+	}
+	return errs
+}
+
+// Units returns the names of the failed units in lexical order.
+func (u UnitErrors) Units() []string {
+	names := make([]string, 0, len(u))
+	for name := range u {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ErrorOrNil returns nil when no unit failed, so an empty UnitErrors is never
+// returned as a non-nil error.
+func (u UnitErrors) ErrorOrNil() error {
+	if len(u) == 0 {
+		return nil
+	}
+	return u
+}
+
+// BatchErrors holds the error of each key of a batch operation, aligned with
+// the queries; a nil entry means the key succeeded.
+type BatchErrors []error
+
+func (b BatchErrors) Error() string {
+	messages := make([]string, 0, len(b))
+	for c, err := range b {
+		if err != nil {
+			messages = append(messages, fmt.Sprintf("key %v: %v", c, err))
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+func (b BatchErrors) Unwrap() []error {
+	errs := make([]error, 0, len(b))
+	for _, err := range b {
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+// ErrorOrNil returns nil when every key succeeded.
+func (b BatchErrors) ErrorOrNil() error {
+	for _, err := range b {
+		if err != nil {
+			return b
+		}
+	}
+	return nil
+}
@@ -0,0 +1,53 @@
+package protocols
+
+import (
+	"context"
+	"time"
+)
+
+type UnitOperation string
+
+const (
+	SaveOperation       UnitOperation = "save"
+	GetOperation        UnitOperation = "get"
+	DeleteOperation     UnitOperation = "delete"
+	SaveManyOperation   UnitOperation = "save-many"
+	GetManyOperation    UnitOperation = "get-many"
+	DeleteManyOperation UnitOperation = "delete-many"
+
+	GetWithMetadataOperation UnitOperation = "get-with-metadata"
+
+	PrepareOperation UnitOperation = "prepare"
+	CommitOperation  UnitOperation = "commit"
+	AbortOperation   UnitOperation = "abort"
+)
+
+// UnitCall describes a single call to a unit as it goes through the
+// interceptors. Query and Item are set for single-key operations, Queries and
+// Items for batches, and Transaction for the operations of a transaction.
+// Value, Values, Errors and Metadata hold what the unit returned, and Duration
+// how long it took, once the call reached the unit.
+type UnitCall[K any, V any] struct {
+	Operation UnitOperation
+	Unit      string
+	Query     K
+	Item      V
+	Queries   []K
+	Items     []V
+
+	Transaction string
+
+	Value    V
+	Values   []V
+	Errors   []error
+	Metadata Metadata
+	Start    time.Time
+	Duration time.Duration
+}
+
+type UnitCallFunc[K any, V any] func(ctx context.Context, call *UnitCall[K, V]) error
+
+// Interceptor wraps the call to a unit. It can inspect or change the call
+// before passing it to next, skip next to fail the call, and inspect the
+// outcome after next returns.
+type Interceptor[K any, V any] func(next UnitCallFunc[K, V]) UnitCallFunc[K, V]
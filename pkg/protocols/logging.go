@@ -0,0 +1,62 @@
+package protocols
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+)
+
+// KeyRedactor turns a key into the value logged in its place, so keys holding
+// personal data never reach the logs.
+type KeyRedactor[K any] func(key K) string
+
+// processSecret keys HashKey. It is drawn once per process, so the hashes of
+// one run cannot be matched against a dictionary of hashed keys or against the
+// logs of another run.
+var processSecret = func() []byte {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		panic(fmt.Sprintf("protocols: reading random key: %v", err))
+	}
+	return secret
+}()
+
+// HashKey is the default KeyRedactor: the first 16 hex digits of the
+// HMAC-SHA256 of the key printed with fmt, under a secret drawn when the
+// process starts. Equal keys get equal hashes within a process, so the logs of
+// a key can still be correlated; use HMACKey to correlate them across
+// restarts.
+func HashKey[K any](key K) string {
+	return hashKey(processSecret, key)
+}
+
+// HMACKey returns a KeyRedactor like HashKey keyed with secret, so a key gets
+// the same hash in every process sharing the secret. The secret must be kept
+// out of the logs: anyone holding it can test guesses against the hashes.
+func HMACKey[K any](secret []byte) KeyRedactor[K] {
+	secret = append([]byte(nil), secret...)
+	return func(key K) string {
+		return hashKey(secret, key)
+	}
+}
+
+func hashKey[K any](secret []byte, key K) string {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(fmt.Sprint(key)))
+	return hex.EncodeToString(mac.Sum(nil)[:8])
+}
+
+type LoggerOptions[K any] struct {
+	RedactKey KeyRedactor[K]
+}
+
+type LoggerOptionsFunc[K any] func(*LoggerOptions[K])
+
+// WithKeyRedactor replaces HashKey as the way keys are logged.
+func WithKeyRedactor[K any](redact KeyRedactor[K]) LoggerOptionsFunc[K] {
+	return func(opt *LoggerOptions[K]) {
+		opt.RedactKey = redact
+	}
+}
@@ -0,0 +1,126 @@
+package protocols
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// OperationInfo describes an operation of the orchestrator once its strategy
+// returned. Keys is the number of keys the operation worked on, and Get is set
+// for get operations.
+type OperationInfo struct {
+	Operation UnitOperation
+	Strategy  string
+	Keys      int
+	Start     time.Time
+	Duration  time.Duration
+	Err       error
+	Get       *GetReport
+}
+
+type OperationObserver func(info OperationInfo)
+
+// GetHit records that a key was found in Unit, at position Tier of the targets.
+type GetHit struct {
+	Unit string
+	Tier int
+}
+
+// GetReport is filled in by the get strategies that support it, the Cache and
+// Race strategies among the built-in ones, with where each key was found and
+// which units it was saved back into, or failed to be. Its methods can be
+// called on a nil report, so strategies report unconditionally.
+type GetReport struct {
+	mu              sync.Mutex
+	hits            []GetHit
+	backfills       map[string]int
+	failedBackfills map[string]error
+}
+
+func (r *GetReport) Hit(unit string, tier int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.hits = append(r.hits, GetHit{Unit: unit, Tier: tier})
+}
+
+// Backfill records that keys keys were saved back into unit.
+func (r *GetReport) Backfill(unit string, keys int) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.backfills == nil {
+		r.backfills = map[string]int{}
+	}
+	r.backfills[unit] += keys
+}
+
+// BackfillFailed records that saving back into unit failed with err.
+func (r *GetReport) BackfillFailed(unit string, err error) {
+	if r == nil {
+		return
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.failedBackfills == nil {
+		r.failedBackfills = map[string]error{}
+	}
+	r.failedBackfills[unit] = err
+}
+
+func (r *GetReport) Hits() []GetHit {
+	if r == nil {
+		return nil
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]GetHit(nil), r.hits...)
+}
+
+// Backfills returns the number of keys saved back into each unit.
+func (r *GetReport) Backfills() map[string]int {
+	backfills := map[string]int{}
+	if r == nil {
+		return backfills
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for unit, keys := range r.backfills {
+		backfills[unit] = keys
+	}
+	return backfills
+}
+
+// FailedBackfills returns the error of each unit the value could not be saved
+// back into.
+func (r *GetReport) FailedBackfills() map[string]error {
+	failed := map[string]error{}
+	if r == nil {
+		return failed
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for unit, err := range r.failedBackfills {
+		failed[unit] = err
+	}
+	return failed
+}
+
+type getReportKey struct{}
+
+// ContextWithGetReport returns a context that carries report to the get
+// strategy.
+func ContextWithGetReport(ctx context.Context, report *GetReport) context.Context {
+	return context.WithValue(ctx, getReportKey{}, report)
+}
+
+// GetReportFromContext returns the report carried by ctx, or nil.
+func GetReportFromContext(ctx context.Context) *GetReport {
+	report, _ := ctx.Value(getReportKey{}).(*GetReport)
+	return report
+}
@@ -0,0 +1,23 @@
+package protocols
+
+import "context"
+
+// PendingWrite is a write the WriteBehind save strategy still owes to Targets.
+type PendingWrite[K any, V any] struct {
+	Query   K
+	Item    V
+	Targets []string
+}
+
+// WriteQueue holds the pending writes of the WriteBehind save strategy in
+// order. Writes are only removed with Ack once they were flushed, so a
+// durable queue hands the writes that were not flushed again after a restart.
+type WriteQueue[K any, V any] interface {
+	Push(ctx context.Context, writes ...PendingWrite[K, V]) error
+	// Peek returns up to n writes from the head of the queue without removing
+	// them.
+	Peek(ctx context.Context, n int) ([]PendingWrite[K, V], error)
+	// Ack removes n writes from the head of the queue.
+	Ack(ctx context.Context, n int) error
+	Len() int
+}
@@ -0,0 +1,36 @@
+package protocols
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// RetryPolicy describes how a failed unit call is retried. The wait before the
+// n-th retry is InitialBackoff * Multiplier^(n-1), capped at MaxBackoff and
+// shifted by up to Jitter (a fraction of the wait) in either direction.
+type RetryPolicy struct {
+	MaxAttempts    int
+	InitialBackoff time.Duration
+	MaxBackoff     time.Duration
+	Multiplier     float64
+	Jitter         float64
+	Retryable      func(error) bool
+}
+
+// IsRetryable reports whether err should be retried. Without a Retryable
+// classifier every error is retried except clean misses, open circuit breakers,
+// missing metadata or transaction support and context errors; a unit timeout
+// is retried since the operation still had time left.
+func (r RetryPolicy) IsRetryable(err error) bool {
+	if r.Retryable != nil {
+		return r.Retryable(err)
+	}
+
+	var timeout *UnitTimeoutError
+	if errors.As(err, &timeout) {
+		return true
+	}
+	return !errors.Is(err, ErrNotFound) && !errors.Is(err, ErrCircuitOpen) && !errors.Is(err, ErrMetadataUnsupported) &&
+		!errors.Is(err, ErrTransactionsUnsupported) && !errors.Is(err, context.Canceled) && !errors.Is(err, context.DeadlineExceeded)
+}
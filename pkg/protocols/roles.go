@@ -0,0 +1,116 @@
+package protocols
+
+import (
+	"context"
+	"sort"
+)
+
+// UnitRole is what a unit is for. A PrimaryRole unit is a source of truth for
+// the items, a ReplicaRole unit a durable copy of one, a CacheRole unit a copy
+// that may lose items at any time and an ArchiveRole unit a store of items
+// that are rarely read. Units added without a role have none, and strategies
+// that need a source of truth then use the last target.
+type UnitRole string
+
+const (
+	CacheRole   UnitRole = "cache"
+	PrimaryRole UnitRole = "primary"
+	ReplicaRole UnitRole = "replica"
+	ArchiveRole UnitRole = "archive"
+)
+
+// Durability tells whether a unit keeps its items. A miss in a Volatile unit,
+// like a miss in a CacheRole unit, does not prove that the item does not
+// exist.
+type Durability string
+
+const (
+	Volatile Durability = "volatile"
+	Durable  Durability = "durable"
+)
+
+// UnitInfo describes a unit of the orchestrator and the options it was added
+// with.
+type UnitInfo struct {
+	Name string
+	UnitOptions
+}
+
+type unitOptionsKey struct{}
+
+// ContextWithUnitOptions returns a context that carries the options of every
+// unit to the strategy. The orchestrator calls it for every operation.
+func ContextWithUnitOptions(ctx context.Context, options map[string]UnitOptions) context.Context {
+	return context.WithValue(ctx, unitOptionsKey{}, options)
+}
+
+// UnitOptionsFromContext returns the options of the units carried by ctx, or
+// nil.
+func UnitOptionsFromContext(ctx context.Context) map[string]UnitOptions {
+	options, _ := ctx.Value(unitOptionsKey{}).(map[string]UnitOptions)
+	return options
+}
+
+// SourceTargets returns the targets whose unit has the PrimaryRole, highest
+// Priority first, or the last target when none has it.
+func SourceTargets(ctx context.Context, targets []string) []string {
+	options := UnitOptionsFromContext(ctx)
+	var sources []string
+	for _, target := range targets {
+		if options[target].Role == PrimaryRole {
+			sources = append(sources, target)
+		}
+	}
+	if len(sources) == 0 && len(targets) > 0 {
+		return targets[len(targets)-1:]
+	}
+	sort.SliceStable(sources, func(i, j int) bool {
+		return options[sources[i]].Priority > options[sources[j]].Priority
+	})
+	return sources
+}
+
+// DisposableTargets returns the targets whose unit is not explicitly marked
+// durable, in order: every unit except the PrimaryRole and ReplicaRole ones and
+// the ones added with Durable. Units added without options are disposable.
+func DisposableTargets(ctx context.Context, targets []string) []string {
+	options := UnitOptionsFromContext(ctx)
+	var disposable []string
+	for _, target := range targets {
+		unit := options[target]
+		if unit.Role != PrimaryRole && unit.Role != ReplicaRole && unit.Durability != Durable {
+			disposable = append(disposable, target)
+		}
+	}
+	return disposable
+}
+
+// WritableTargets returns the targets whose unit is not read-only.
+func WritableTargets(ctx context.Context, targets []string) []string {
+	options := UnitOptionsFromContext(ctx)
+	writable := make([]string, 0, len(targets))
+	for _, target := range targets {
+		if !options[target].ReadOnly {
+			writable = append(writable, target)
+		}
+	}
+	return writable
+}
+
+// Authoritative reports whether a miss in the unit proves that the item does
+// not exist, which is the case unless the unit is a cache or volatile.
+func (u UnitOptions) Authoritative() bool {
+	return u.Role != CacheRole && u.Durability != Volatile
+}
+
+// AuthoritativeMiss reports whether any of the units that missed an item is
+// authoritative.
+func AuthoritativeMiss(ctx context.Context, missed []string) bool {
+	options := UnitOptionsFromContext(ctx)
+	for _, unit := range missed {
+		if options[unit].Authoritative() {
+			return true
+		}
+	}
+	return false
+}
@@ -1,35 +1,80 @@
 package protocols
 
-import "context"
+import (
+	"context"
+	"log/slog"
+	"time"
+)
+
+// The option types name the strategy an operation runs with. The constants are
+// the names the built-in strategies are registered under; custom strategies can
+// be registered under any other name.
+type TypeGetOptions string
+type TypeSaveOptions string
+type TypeDeleteOptions string
 
-type TypeGetOptions uint
-type TypeSaveOptions uint
-type TypeDeleteOptions uint
+const (
+	Sequential TypeSaveOptions = "sequential"
+	Parallel   TypeSaveOptions = "parallel"
+	Quorum     TypeSaveOptions = "quorum"
+
+	WriteBehind    TypeSaveOptions = "write-behind"
+	WriteAround    TypeSaveOptions = "write-around"
+	SourceOnly     TypeSaveOptions = "source-only"
+	Atomic         TypeSaveOptions = "atomic"
+	AtomicParallel TypeSaveOptions = "atomic-parallel"
+	TwoPhase       TypeSaveOptions = "two-phase"
+)
 
 const (
-	Sequential TypeSaveOptions = iota
-	Parallel
+	SequentialDelete TypeDeleteOptions = "sequential"
+	ParallelDelete   TypeDeleteOptions = "parallel"
+	BestEffortDelete TypeDeleteOptions = "best-effort"
 )
 
 const (
-	SequentialDelete TypeDeleteOptions = iota
+	Cache     TypeGetOptions = "cache"
+	Race      TypeGetOptions = "race"
+	QuorumGet TypeGetOptions = "quorum"
+
+	StaleWhileRevalidate TypeGetOptions = "stale-while-revalidate"
 )
 
+type TypeRemoveOptions string
+
 const (
-	Cache TypeGetOptions = iota
-	Race
+	DropFromStandardOrder   TypeRemoveOptions = "drop"
+	RefuseIfInStandardOrder TypeRemoveOptions = "refuse"
 )
 
 type StorageOrchestrator[K any, V any] interface {
 	Save(query K, item V, opt ...SaveOptionsFunc) ([]string, error)
 	Get(query K, opt ...GetOptionsFunc) (V, error)
-	Delete(query K, opt ...DeleteOptionsFunc) error
+	Delete(query K, opt ...DeleteOptionsFunc) ([]string, error)
+
+	SaveMany(queries []K, items []V, opt ...SaveOptionsFunc) ([]string, error)
+	GetMany(queries []K, opt ...GetOptionsFunc) ([]V, error)
+	DeleteMany(queries []K, opt ...DeleteOptionsFunc) ([]string, error)
 
-	AddUnit(storageName string, storage StorageUnit[K, V]) error
+	AddUnit(storageName string, storage StorageUnit[K, V], opt ...UnitOptionsFunc) error
+	RemoveUnit(storageName string, opt ...RemoveOptionsFunc) error
+	ReplaceUnit(storageName string, storage StorageUnit[K, V]) error
 	GetUnits() (map[string]StorageUnit[K, V], error)
 	GetUnit(string) (StorageUnit[K, V], error)
+	GetUnitInfo(string) (UnitInfo, error)
 
 	SetStandardOrder(targets ...string) error
+
+	Use(interceptors ...Interceptor[K, V])
+	Observe(observers ...OperationObserver)
+	SetTracer(tracer Tracer)
+	SetLogger(logger *slog.Logger, opts ...LoggerOptionsFunc[K])
+	SetCoalescing(key KeyFunc[K])
+	SetNegativeCache(ttl time.Duration, key KeyFunc[K])
+
+	RegisterSaveStrategy(name TypeSaveOptions, strategy SaveStrategy[K, V]) error
+	RegisterGetStrategy(name TypeGetOptions, strategy GetStrategy[K, V]) error
+	RegisterDeleteStrategy(name TypeDeleteOptions, strategy DeleteStrategy[K, V]) error
 }
 
 type SaveOptionsFunc func(*SaveOptions)
@@ -38,30 +83,260 @@ type GetOptionsFunc func(*GetOptions)
 
 type DeleteOptionsFunc func(*DeleteOptions)
 
+type RemoveOptionsFunc func(*RemoveOptions)
+
+type UnitOptionsFunc func(*UnitOptions)
+
 type SaveOptions struct {
 	Context       context.Context
 	HowWillItSave TypeSaveOptions
 	Targets       []string
+	WriteQuorum   int
+	Retry         *RetryPolicy
+	Timeout       time.Duration
+}
+
+// WithWriteQuorum selects the Quorum save strategy, which succeeds once n of
+// the targets acknowledge the write. A zero quorum means a majority of the
+// targets.
+func WithWriteQuorum(n int) SaveOptionsFunc {
+	return func(opt *SaveOptions) {
+		opt.HowWillItSave = Quorum
+		opt.WriteQuorum = n
+	}
 }
 
 type GetOptions struct {
 	Context      context.Context
 	HowWillItGet TypeGetOptions
 	Targets      []string
+	ReadQuorum   int
+	Retry        *RetryPolicy
+	Timeout      time.Duration
+}
+
+// WithReadQuorum selects the QuorumGet strategy, which resolves the value from
+// the first n targets that answer. A zero quorum means a majority of the
+// targets.
+func WithReadQuorum(n int) GetOptionsFunc {
+	return func(opt *GetOptions) {
+		opt.HowWillItGet = QuorumGet
+		opt.ReadQuorum = n
+	}
 }
 
 type DeleteOptions struct {
 	Context         context.Context
 	Targets         []string
 	HowWillItDelete TypeDeleteOptions
+	Retry           *RetryPolicy
+	Timeout         time.Duration
+}
+
+// UnitOptions configures a unit when it is added to the orchestrator.
+type UnitOptions struct {
+	Retry      *RetryPolicy
+	Timeout    time.Duration
+	Role       UnitRole
+	Durability Durability
+	ReadOnly   bool
+	Priority   int
+	Tags       []string
+}
+
+// WithUnitRetry retries every call made to the unit according to policy,
+// unless the operation sets its own policy.
+func WithUnitRetry(policy RetryPolicy) UnitOptionsFunc {
+	return func(opt *UnitOptions) {
+		opt.Retry = &policy
+	}
+}
+
+// WithUnitTimeout bounds every call made to the unit, each retry attempt
+// included, to timeout.
+func WithUnitTimeout(timeout time.Duration) UnitOptionsFunc {
+	return func(opt *UnitOptions) {
+		opt.Timeout = timeout
+	}
+}
+
+// WithUnitRole tells the strategies what the unit is for; see UnitRole.
+func WithUnitRole(role UnitRole) UnitOptionsFunc {
+	return func(opt *UnitOptions) {
+		opt.Role = role
+	}
+}
+
+// WithUnitDurability tells whether the unit keeps its items; see Durability.
+func WithUnitDurability(durability Durability) UnitOptionsFunc {
+	return func(opt *UnitOptions) {
+		opt.Durability = durability
+	}
+}
+
+// WithUnitReadOnly keeps the orchestrator from writing to the unit: saves,
+// deletes and backfills skip it, and an operation whose targets are all
+// read-only returns ErrReadOnlyUnit.
+func WithUnitReadOnly() UnitOptionsFunc {
+	return func(opt *UnitOptions) {
+		opt.ReadOnly = true
+	}
+}
+
+// WithUnitPriority orders units of the same role, highest first, where a
+// strategy has to pick among them, such as the sources of truth.
+func WithUnitPriority(priority int) UnitOptionsFunc {
+	return func(opt *UnitOptions) {
+		opt.Priority = priority
+	}
+}
+
+// WithUnitTags labels the unit; the orchestrator only stores the tags and
+// returns them from GetUnitInfo.
+func WithUnitTags(tags ...string) UnitOptionsFunc {
+	return func(opt *UnitOptions) {
+		opt.Tags = append(opt.Tags, tags...)
+	}
+}
+
+// WithSaveTimeout, WithGetTimeout and WithDeleteTimeout set a deadline for the
+// whole operation. Unit calls the operation leaves running, such as quorum
+// stragglers, are canceled when it returns.
+func WithSaveTimeout(timeout time.Duration) SaveOptionsFunc {
+	return func(opt *SaveOptions) {
+		opt.Timeout = timeout
+	}
+}
+
+func WithGetTimeout(timeout time.Duration) GetOptionsFunc {
+	return func(opt *GetOptions) {
+		opt.Timeout = timeout
+	}
+}
+
+func WithDeleteTimeout(timeout time.Duration) DeleteOptionsFunc {
+	return func(opt *DeleteOptions) {
+		opt.Timeout = timeout
+	}
+}
+
+// WithSaveRetry, WithGetRetry and WithDeleteRetry retry every unit call of a
+// single operation according to policy, overriding the policies of the units.
+func WithSaveRetry(policy RetryPolicy) SaveOptionsFunc {
+	return func(opt *SaveOptions) {
+		opt.Retry = &policy
+	}
 }
 
+func WithGetRetry(policy RetryPolicy) GetOptionsFunc {
+	return func(opt *GetOptions) {
+		opt.Retry = &policy
+	}
+}
+
+func WithDeleteRetry(policy RetryPolicy) DeleteOptionsFunc {
+	return func(opt *DeleteOptions) {
+		opt.Retry = &policy
+	}
+}
+
+// RemoveOptions decides what RemoveUnit does with a unit that is part of the
+// standard order: DropFromStandardOrder removes it from the order as well,
+// RefuseIfInStandardOrder keeps the unit and returns ErrUnitInUse.
+type RemoveOptions struct {
+	HowWillItRemove TypeRemoveOptions
+}
+
+func WithRemovePolicy(policy TypeRemoveOptions) RemoveOptionsFunc {
+	return func(opt *RemoveOptions) {
+		opt.HowWillItRemove = policy
+	}
+}
+
+func WithSaveStrategy(name TypeSaveOptions) SaveOptionsFunc {
+	return func(opt *SaveOptions) {
+		opt.HowWillItSave = name
+	}
+}
+
+func WithGetStrategy(name TypeGetOptions) GetOptionsFunc {
+	return func(opt *GetOptions) {
+		opt.HowWillItGet = name
+	}
+}
+
+func WithDeleteStrategy(name TypeDeleteOptions) DeleteOptionsFunc {
+	return func(opt *DeleteOptions) {
+		opt.HowWillItDelete = name
+	}
+}
+
+// KeyFunc derives a comparable identity from a key: two keys must get the same
+// string exactly when they name the same item.
+type KeyFunc[K any] func(key K) string
+
+// StorageUnit is a single storage backend. Get must return an error wrapping
+// ErrNotFound when the item does not exist in the unit, so strategies can tell
+// a clean miss from a failure.
 type StorageUnit[K any, V any] interface {
 	Save(ctx context.Context, query K, item V) error
 	Get(ctx context.Context, query K) (V, error)
 	Delete(ctx context.Context, query K) error
 }
 
+// AvailabilityReporter is implemented by units that know in advance that a call
+// would fail, such as a tripped circuit breaker, so strategies can skip them.
+type AvailabilityReporter interface {
+	Available() bool
+}
+
+// UnitWrapper is implemented by units that decorate another unit, so the
+// decorated unit can be inspected through the decorator.
+type UnitWrapper[K any, V any] interface {
+	Unwrap() StorageUnit[K, V]
+}
+
+// Metadata describes a value as stored in a unit: when it was written, and
+// whether the unit already knows it is out of date.
+type Metadata struct {
+	WrittenAt time.Time
+	Stale     bool
+}
+
+// MetadataStorageUnit is implemented by units that can tell when a value was
+// written, which strategies such as StaleWhileRevalidate use to decide whether
+// the value needs refreshing. The decorators of this module forward
+// GetWithMetadata, returning ErrMetadataUnsupported when the unit they wrap
+// does not implement it.
+type MetadataStorageUnit[K any, V any] interface {
+	StorageUnit[K, V]
+	GetWithMetadata(ctx context.Context, query K) (V, Metadata, error)
+}
+
+// TransactionalStorageUnit is implemented by units backed by transactional
+// stores. Prepare stages the write of item under the transaction tx, and must
+// only succeed when a later Commit of tx is certain to succeed; a transaction
+// can stage many writes. Commit applies every write staged under tx and Abort
+// discards them. The decorators of this module forward the three methods,
+// returning ErrTransactionsUnsupported when the unit they wrap does not
+// implement them.
+type TransactionalStorageUnit[K any, V any] interface {
+	StorageUnit[K, V]
+	Prepare(ctx context.Context, tx string, query K, item V) error
+	Commit(ctx context.Context, tx string) error
+	Abort(ctx context.Context, tx string) error
+}
+
+// BatchStorageUnit is implemented by units with native multi-key operations.
+// The results of GetMany are aligned with queries; a nil error means the value
+// was found. Units that do not implement it are called once per key.
+type BatchStorageUnit[K any, V any] interface {
+	StorageUnit[K, V]
+	SaveMany(ctx context.Context, queries []K, items []V) error
+	GetMany(ctx context.Context, queries []K) ([]V, []error)
+	DeleteMany(ctx context.Context, queries []K) error
+}
+
 type SaveStrategy[K any, V any] interface {
 	Save(ctx context.Context, query K, item V, units map[string]StorageUnit[K, V], targets []string, auxiliary ...any) ([]string, error)
 }
@@ -71,5 +346,47 @@ type GetStrategy[K any, V any] interface {
 }
 
 type DeleteStrategy[K any, V any] interface {
-	Delete(ctx context.Context, query K, units map[string]StorageUnit[K, V], targets []string, auxiliary ...any) error
+	Delete(ctx context.Context, query K, units map[string]StorageUnit[K, V], targets []string, auxiliary ...any) ([]string, error)
+}
+
+// The batch strategy interfaces are optional: a strategy selected for SaveMany,
+// GetMany or DeleteMany must implement the matching one. Per-key failures are
+// reported as a BatchErrors aligned with queries.
+type BatchSaveStrategy[K any, V any] interface {
+	SaveMany(ctx context.Context, queries []K, items []V, units map[string]StorageUnit[K, V], targets []string, auxiliary ...any) ([]string, error)
+}
+
+type BatchGetStrategy[K any, V any] interface {
+	GetMany(ctx context.Context, queries []K, units map[string]StorageUnit[K, V], targets []string, auxiliary ...any) ([]V, error)
+}
+
+type BatchDeleteStrategy[K any, V any] interface {
+	DeleteMany(ctx context.Context, queries []K, units map[string]StorageUnit[K, V], targets []string, auxiliary ...any) ([]string, error)
+}
+
+// DeferredSaveStrategy is implemented by save strategies that write to some
+// targets after Save returned, such as write-behind. When the strategy is
+// registered, the orchestrator hands it written, which the strategy calls with
+// the keys of every deferred write once it reached the units, so the
+// orchestrator can drop what it remembers about them, like cached misses.
+type DeferredSaveStrategy[K any, V any] interface {
+	SaveStrategy[K, V]
+	OnDeferredWrite(written func(queries ...K))
+}
+
+type settledKey struct{}
+
+// ContextWithSettled returns a context that carries settled to the save
+// strategy. A strategy whose writes may still be running when Save returns,
+// such as Quorum, calls it once the last of them returned, so the orchestrator
+// can drop what it remembers about the key again. The orchestrator calls it for
+// every save.
+func ContextWithSettled(ctx context.Context, settled func()) context.Context {
+	return context.WithValue(ctx, settledKey{}, settled)
+}
+
+// SettledFromContext returns the function carried by ctx, or nil.
+func SettledFromContext(ctx context.Context) func() {
+	settled, _ := ctx.Value(settledKey{}).(func())
+	return settled
 }
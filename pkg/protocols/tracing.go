@@ -0,0 +1,63 @@
+package protocols
+
+import (
+	"context"
+	"errors"
+)
+
+// Tracer is the vendor-neutral interface the orchestrator emits spans through:
+// one span per operation and, as its children, one span per unit call.
+// Start returns a context carrying the new span, so spans started with it
+// become its children.
+type Tracer interface {
+	Start(ctx context.Context, name string, attributes ...Attribute) (context.Context, Span)
+}
+
+type Span interface {
+	SetAttributes(attributes ...Attribute)
+	// RecordError marks the span as failed with err.
+	RecordError(err error)
+	End()
+}
+
+// Attribute is a key-value pair attached to a span. Value is a string, bool,
+// int, int64 or float64.
+type Attribute struct {
+	Key   string
+	Value any
+}
+
+const (
+	AttributeOperation = "storage.operation"
+	AttributeStrategy  = "storage.strategy"
+	AttributeUnit      = "storage.unit"
+	AttributeKeys      = "storage.keys"
+	AttributeOutcome   = "storage.outcome"
+)
+
+const (
+	OutcomeSuccess = "success"
+	OutcomeMiss    = "miss"
+	OutcomeError   = "error"
+)
+
+// Outcome sorts the error of an operation or unit call into OutcomeSuccess,
+// OutcomeMiss for a clean miss, confirmed by an authoritative unit or not, or
+// OutcomeError. A batch is a miss when every key that failed was a clean miss.
+func Outcome(err error) string {
+	var batch BatchErrors
+	switch {
+	case err == nil:
+		return OutcomeSuccess
+	case errors.As(err, &batch):
+		for _, err := range batch {
+			if Outcome(err) == OutcomeError {
+				return OutcomeError
+			}
+		}
+		return OutcomeMiss
+	case errors.Is(err, ErrNotFound), errors.Is(err, ErrUnconfirmedMiss):
+		return OutcomeMiss
+	}
+	return OutcomeError
+}
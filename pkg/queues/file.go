@@ -0,0 +1,213 @@
+package queues
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// compactAfter is how many acknowledged writes the log keeps before it is
+// rewritten with only the pending ones.
+const compactAfter = 1024
+
+// fileRecord is a line of the log: either a pushed write or the number of
+// writes acknowledged from the head.
+type fileRecord[K any, V any] struct {
+	Write *protocols.PendingWrite[K, V] `json:"write,omitempty"`
+	Ack   int                           `json:"ack,omitempty"`
+}
+
+// FileQueue keeps the pending writes in an append-only log of JSON lines, so
+// they survive a restart; keys and items must therefore be JSON encodable.
+// Every Push is synced to disk before it returns, and a Push or Ack that fails
+// to write leaves the log as it was. The log is rewritten once it holds
+// compactAfter acknowledged writes; when that fails, it is kept as it is and
+// rewritten on a later Ack.
+type FileQueue[K any, V any] struct {
+	mu     sync.Mutex
+	path   string
+	file   *os.File
+	size   int64
+	writes []protocols.PendingWrite[K, V]
+	acked  int
+}
+
+// OpenFileQueue opens the log at path, creating it when it does not exist, and
+// replays it. A last line cut short by a crash is dropped.
+func OpenFileQueue[K any, V any](path string) (*FileQueue[K, V], error) {
+	file, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0o644)
+	if err != nil {
+		return nil, err
+	}
+
+	queue := &FileQueue[K, V]{path: path, file: file}
+	if err := queue.replay(); err != nil {
+		file.Close()
+		return nil, err
+	}
+	return queue, nil
+}
+
+func (f *FileQueue[K, V]) replay() error {
+	reader := bufio.NewReader(f.file)
+	var offset int64
+	for line := 1; ; line++ {
+		data, err := reader.ReadBytes('\n')
+		if errors.Is(err, io.EOF) {
+			f.size = offset
+			if len(data) > 0 {
+				return f.file.Truncate(offset)
+			}
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		var record fileRecord[K, V]
+		if err := json.Unmarshal(data, &record); err != nil {
+			return fmt.Errorf("reading line %v of %v: %w", line, f.path, err)
+		}
+		if record.Write != nil {
+			f.writes = append(f.writes, *record.Write)
+		}
+		f.writes = ack(f.writes, record.Ack)
+		f.acked += record.Ack
+		offset += int64(len(data))
+	}
+}
+
+func (f *FileQueue[K, V]) Push(ctx context.Context, writes ...protocols.PendingWrite[K, V]) error {
+	var buffer bytes.Buffer
+	for c := range writes {
+		if err := json.NewEncoder(&buffer).Encode(fileRecord[K, V]{Write: &writes[c]}); err != nil {
+			return err
+		}
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if err := f.append(buffer.Bytes()); err != nil {
+		return err
+	}
+	f.writes = append(f.writes, writes...)
+	return nil
+}
+
+func (f *FileQueue[K, V]) Peek(ctx context.Context, n int) ([]protocols.PendingWrite[K, V], error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return peek(f.writes, n), nil
+}
+
+func (f *FileQueue[K, V]) Ack(ctx context.Context, n int) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if n > len(f.writes) {
+		n = len(f.writes)
+	}
+	if n <= 0 {
+		return nil
+	}
+
+	data, err := json.Marshal(fileRecord[K, V]{Ack: n})
+	if err != nil {
+		return err
+	}
+	if err := f.append(append(data, '\n')); err != nil {
+		return err
+	}
+	f.writes = ack(f.writes, n)
+	f.acked += n
+
+	// The writes are acknowledged already, so a failed compaction is not an
+	// error of Ack; the log keeps its records and the next Ack tries again.
+	if f.acked >= compactAfter {
+		f.compact()
+	}
+	return nil
+}
+
+// append writes data at the end of the log and syncs it. When either fails,
+// the log is cut back to its last complete record, so a record written in part
+// never ends up in the middle of the log. The caller must hold f.mu.
+func (f *FileQueue[K, V]) append(data []byte) error {
+	_, err := f.file.Write(data)
+	if err == nil {
+		err = f.file.Sync()
+	}
+	if err != nil {
+		if truncateErr := os.Truncate(f.path, f.size); truncateErr != nil {
+			return errors.Join(err, fmt.Errorf("restoring %v: %w", f.path, truncateErr))
+		}
+		return err
+	}
+	f.size += int64(len(data))
+	return nil
+}
+
+func (f *FileQueue[K, V]) Len() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.writes)
+}
+
+// compact replaces the log with one holding only the pending writes. The new
+// log is written and opened before it replaces the old one, so f.file always
+// refers to the file at f.path. The caller must hold f.mu.
+func (f *FileQueue[K, V]) compact() error {
+	tmp := f.path + ".tmp"
+	file, err := os.OpenFile(tmp, os.O_RDWR|os.O_CREATE|os.O_TRUNC|os.O_APPEND, 0o644)
+	if err != nil {
+		return err
+	}
+
+	writer := bufio.NewWriter(file)
+	encoder := json.NewEncoder(writer)
+	for c := range f.writes {
+		if err = encoder.Encode(fileRecord[K, V]{Write: &f.writes[c]}); err != nil {
+			break
+		}
+	}
+	if err == nil {
+		err = writer.Flush()
+	}
+	if err == nil {
+		err = file.Sync()
+	}
+	var info os.FileInfo
+	if err == nil {
+		info, err = file.Stat()
+	}
+	if err == nil {
+		err = os.Rename(tmp, f.path)
+	}
+	if err != nil {
+		file.Close()
+		os.Remove(tmp)
+		return err
+	}
+
+	f.file.Close()
+	f.file = file
+	f.size = info.Size()
+	f.acked = 0
+	return nil
+}
+
+func (f *FileQueue[K, V]) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.file.Close()
+}
+
+var _ protocols.WriteQueue[any, any] = (*FileQueue[any, any])(nil)
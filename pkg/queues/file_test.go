@@ -0,0 +1,146 @@
+package queues
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	"github.com/stretchr/testify/assert"
+)
+
+func write(query string) protocols.PendingWrite[string, int] {
+	return protocols.PendingWrite[string, int]{Query: query, Item: len(query), Targets: []string{"slow"}}
+}
+
+func TestFileQueue(t *testing.T) {
+
+	t.Run("should replay the writes that were not acknowledged", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "writes.log")
+		ctx := context.Background()
+
+		queue, err := OpenFileQueue[string, int](path)
+		assert.NoError(t, err)
+		assert.NoError(t, queue.Push(ctx, write("a"), write("bb")))
+		assert.NoError(t, queue.Push(ctx, write("ccc")))
+		assert.NoError(t, queue.Ack(ctx, 1))
+		assert.NoError(t, queue.Close())
+
+		queue, err = OpenFileQueue[string, int](path)
+		assert.NoError(t, err)
+		defer queue.Close()
+
+		assert.Equal(t, 2, queue.Len())
+		writes, err := queue.Peek(ctx, 10)
+		assert.NoError(t, err)
+		assert.Equal(t, []protocols.PendingWrite[string, int]{write("bb"), write("ccc")}, writes)
+	})
+
+	t.Run("should drop a last line cut short", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "writes.log")
+		ctx := context.Background()
+
+		queue, err := OpenFileQueue[string, int](path)
+		assert.NoError(t, err)
+		assert.NoError(t, queue.Push(ctx, write("a")))
+		assert.NoError(t, queue.Close())
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+		assert.NoError(t, err)
+		file.WriteString(`{"write":{"Query":"b`)
+		file.Close()
+
+		queue, err = OpenFileQueue[string, int](path)
+		assert.NoError(t, err)
+		assert.Equal(t, 1, queue.Len())
+		assert.NoError(t, queue.Push(ctx, write("c")))
+		assert.NoError(t, queue.Close())
+
+		queue, err = OpenFileQueue[string, int](path)
+		assert.NoError(t, err)
+		defer queue.Close()
+		writes, _ := queue.Peek(ctx, 10)
+		assert.Equal(t, []protocols.PendingWrite[string, int]{write("a"), write("c")}, writes)
+	})
+
+	t.Run("should cut a failed write out of the log", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "writes.log")
+		ctx := context.Background()
+
+		queue, err := OpenFileQueue[string, int](path)
+		assert.NoError(t, err)
+		assert.NoError(t, queue.Push(ctx, write("a")))
+
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0o644)
+		assert.NoError(t, err)
+		file.WriteString(`{"write":{"Query":"b`)
+		file.Close()
+		writable := queue.file
+		queue.file, err = os.Open(path)
+		assert.NoError(t, err)
+
+		assert.Error(t, queue.Push(ctx, write("b")))
+		queue.file.Close()
+		queue.file = writable
+		assert.NoError(t, queue.Push(ctx, write("c")))
+		assert.NoError(t, queue.Close())
+
+		queue, err = OpenFileQueue[string, int](path)
+		assert.NoError(t, err)
+		defer queue.Close()
+		writes, _ := queue.Peek(ctx, 10)
+		assert.Equal(t, []protocols.PendingWrite[string, int]{write("a"), write("c")}, writes)
+	})
+
+	t.Run("should compact the log once enough writes were acknowledged", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "writes.log")
+		ctx := context.Background()
+
+		queue, err := OpenFileQueue[string, int](path)
+		assert.NoError(t, err)
+		for c := 0; c < compactAfter; c++ {
+			assert.NoError(t, queue.Push(ctx, write(fmt.Sprint(c))))
+		}
+		assert.NoError(t, queue.Push(ctx, write("last")))
+		before, _ := os.Stat(path)
+
+		assert.NoError(t, queue.Ack(ctx, compactAfter))
+		after, _ := os.Stat(path)
+		assert.Less(t, after.Size(), before.Size())
+
+		assert.NoError(t, queue.Push(ctx, write("next")))
+		assert.NoError(t, queue.Close())
+
+		queue, err = OpenFileQueue[string, int](path)
+		assert.NoError(t, err)
+		defer queue.Close()
+		writes, _ := queue.Peek(ctx, 10)
+		assert.Equal(t, []protocols.PendingWrite[string, int]{write("last"), write("next")}, writes)
+	})
+
+	t.Run("should acknowledge the writes when the log cannot be compacted", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "writes.log")
+		ctx := context.Background()
+		assert.NoError(t, os.Mkdir(path+".tmp", 0o755))
+
+		queue, err := OpenFileQueue[string, int](path)
+		assert.NoError(t, err)
+		for c := 0; c < compactAfter; c++ {
+			assert.NoError(t, queue.Push(ctx, write(fmt.Sprint(c))))
+		}
+		assert.NoError(t, queue.Push(ctx, write("last")))
+
+		assert.NoError(t, queue.Ack(ctx, compactAfter))
+		assert.Equal(t, 1, queue.Len())
+		assert.NoError(t, queue.Push(ctx, write("next")))
+		assert.NoError(t, queue.Close())
+
+		queue, err = OpenFileQueue[string, int](path)
+		assert.NoError(t, err)
+		defer queue.Close()
+		writes, _ := queue.Peek(ctx, 10)
+		assert.Equal(t, []protocols.PendingWrite[string, int]{write("last"), write("next")}, writes)
+	})
+}
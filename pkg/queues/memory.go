@@ -0,0 +1,68 @@
+// Package queues implements protocols.WriteQueue for the WriteBehind save
+// strategy.
+package queues
+
+import (
+	"context"
+	"sync"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// MemoryQueue keeps the pending writes in memory; they are lost when the
+// process exits.
+type MemoryQueue[K any, V any] struct {
+	mu     sync.Mutex
+	writes []protocols.PendingWrite[K, V]
+}
+
+func NewMemoryQueue[K any, V any]() *MemoryQueue[K, V] {
+	return &MemoryQueue[K, V]{}
+}
+
+func (m *MemoryQueue[K, V]) Push(ctx context.Context, writes ...protocols.PendingWrite[K, V]) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writes = append(m.writes, writes...)
+	return nil
+}
+
+func (m *MemoryQueue[K, V]) Peek(ctx context.Context, n int) ([]protocols.PendingWrite[K, V], error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return peek(m.writes, n), nil
+}
+
+func (m *MemoryQueue[K, V]) Ack(ctx context.Context, n int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writes = ack(m.writes, n)
+	return nil
+}
+
+func (m *MemoryQueue[K, V]) Len() int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return len(m.writes)
+}
+
+func peek[K any, V any](writes []protocols.PendingWrite[K, V], n int) []protocols.PendingWrite[K, V] {
+	if n > len(writes) {
+		n = len(writes)
+	}
+	return append([]protocols.PendingWrite[K, V](nil), writes[:n]...)
+}
+
+func ack[K any, V any](writes []protocols.PendingWrite[K, V], n int) []protocols.PendingWrite[K, V] {
+	if n > len(writes) {
+		n = len(writes)
+	}
+	// Clear the acknowledged writes so they can be collected before the array
+	// behind the slice is replaced.
+	for c := 0; c < n; c++ {
+		writes[c] = protocols.PendingWrite[K, V]{}
+	}
+	return writes[n:]
+}
+
+var _ protocols.WriteQueue[any, any] = (*MemoryQueue[any, any])(nil)
@@ -0,0 +1,79 @@
+package pkg
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+type flight[V any] struct {
+	done    chan struct{}
+	value   V
+	err     error
+	waiters int
+}
+
+// flightGroup coalesces concurrent calls that share a key into one.
+type flightGroup[V any] struct {
+	mu      sync.Mutex
+	flights map[string]*flight[V]
+}
+
+// do runs fn once for all the concurrent calls with the same key and hands its
+// result to each of them. fn runs for the first caller, with its context; the
+// others stop waiting when their own ctx is done. When fn panics, the others
+// get an error and the panic goes on in the first caller.
+func (g *flightGroup[V]) do(ctx context.Context, key string, fn func() (V, error)) (V, error) {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = map[string]*flight[V]{}
+	}
+	if f, ok := g.flights[key]; ok {
+		f.waiters++
+		g.mu.Unlock()
+		defer func() {
+			g.mu.Lock()
+			f.waiters--
+			g.mu.Unlock()
+		}()
+		select {
+		case <-f.done:
+			return f.value, f.err
+		case <-ctx.Done():
+			var value V
+			return value, ctx.Err()
+		}
+	}
+
+	f := &flight[V]{done: make(chan struct{})}
+	g.flights[key] = f
+	g.mu.Unlock()
+
+	defer func() {
+		recovered := recover()
+		if recovered != nil {
+			f.err = fmt.Errorf("coalesced call panicked: %v", recovered)
+		}
+		g.mu.Lock()
+		delete(g.flights, key)
+		g.mu.Unlock()
+		close(f.done)
+		if recovered != nil {
+			panic(recovered)
+		}
+	}()
+
+	f.value, f.err = fn()
+	return f.value, f.err
+}
+
+// waiting returns how many calls are waiting on a call with the same key.
+func (g *flightGroup[V]) waiting() int {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	waiters := 0
+	for _, f := range g.flights {
+		waiters += f.waiters
+	}
+	return waiters
+}
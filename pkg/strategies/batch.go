@@ -0,0 +1,334 @@
+package strategies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// DefaultBatchConcurrency bounds the single-key calls made at once for a unit
+// that does not implement protocols.BatchStorageUnit.
+const DefaultBatchConcurrency = 8
+
+type fanOutBatchUnit[K any, V any] struct {
+	protocols.StorageUnit[K, V]
+	concurrency int
+}
+
+// AsBatchUnit returns the unit itself when it implements
+// protocols.BatchStorageUnit, or an adapter that fans the batch out as
+// single-key calls, at most concurrency at a time.
+func AsBatchUnit[K any, V any](unit protocols.StorageUnit[K, V], concurrency int) protocols.BatchStorageUnit[K, V] {
+	if batch, ok := unit.(protocols.BatchStorageUnit[K, V]); ok {
+		return batch
+	}
+	if concurrency <= 0 {
+		concurrency = DefaultBatchConcurrency
+	}
+	return &fanOutBatchUnit[K, V]{StorageUnit: unit, concurrency: concurrency}
+}
+
+func (f *fanOutBatchUnit[K, V]) SaveMany(ctx context.Context, queries []K, items []V) error {
+	errs := f.each(len(queries), func(c int) error {
+		return f.Save(ctx, queries[c], items[c])
+	})
+	return protocols.BatchErrors(errs).ErrorOrNil()
+}
+
+func (f *fanOutBatchUnit[K, V]) GetMany(ctx context.Context, queries []K) ([]V, []error) {
+	values := make([]V, len(queries))
+	errs := f.each(len(queries), func(c int) error {
+		value, err := f.Get(ctx, queries[c])
+		values[c] = value
+		return err
+	})
+	return values, errs
+}
+
+func (f *fanOutBatchUnit[K, V]) DeleteMany(ctx context.Context, queries []K) error {
+	errs := f.each(len(queries), func(c int) error {
+		return f.Delete(ctx, queries[c])
+	})
+	return protocols.BatchErrors(errs).ErrorOrNil()
+}
+
+func (f *fanOutBatchUnit[K, V]) each(count int, call func(c int) error) []error {
+	errs := make([]error, count)
+	sem := make(chan struct{}, f.concurrency)
+	var wg sync.WaitGroup
+
+	for c := 0; c < count; c++ {
+		wg.Add(1)
+		sem <- struct{}{}
+
+		go func(c int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			errs[c] = call(c)
+		}(c)
+	}
+
+	wg.Wait()
+	return errs
+}
+
+func getMany[K any, V any](ctx context.Context, unit protocols.BatchStorageUnit[K, V], queries []K) ([]V, []error) {
+	values, errs := unit.GetMany(ctx, queries)
+	if len(values) != len(queries) || len(errs) != len(queries) {
+		err := fmt.Errorf("unit returned %v values and %v errors for %v keys", len(values), len(errs), len(queries))
+		values = make([]V, len(queries))
+		errs = make([]error, len(queries))
+		for c := range errs {
+			errs[c] = err
+		}
+	}
+	return values, errs
+}
+
+func pick[T any](items []T, indexes []int) []T {
+	picked := make([]T, len(indexes))
+	for c, index := range indexes {
+		picked[c] = items[index]
+	}
+	return picked
+}
+
+func (s *SequentialSaveStrategy[K, V]) SaveMany(ctx context.Context, queries []K, items []V, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	saved := make([]string, 0, len(targets))
+
+	for _, key := range targets {
+		if ctx.Err() != nil {
+			return saved, ctx.Err()
+		}
+		unit := AsBatchUnit(units[key], s.BatchConcurrency)
+		if err := unit.SaveMany(ctx, queries, items); err != nil {
+			return saved, fmt.Errorf("error saving unit %w", protocols.UnitErrors{key: err})
+		}
+		saved = append(saved, key)
+	}
+	return saved, nil
+}
+
+var _ protocols.BatchSaveStrategy[any, any] = (*SequentialSaveStrategy[any, any])(nil)
+
+func (p *ParallelSaveStrategy[K, V]) SaveMany(ctx context.Context, queries []K, items []V, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var wg sync.WaitGroup
+	mu := sync.Mutex{}
+	saved := make([]string, 0, len(targets))
+	errs := protocols.UnitErrors{}
+
+	for _, key := range targets {
+		wg.Add(1)
+
+		go func(key string, unit protocols.BatchStorageUnit[K, V]) {
+			defer wg.Done()
+
+			err := unit.SaveMany(ctx, queries, items)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				cancel()
+				errs[key] = err
+				return
+			}
+			saved = append(saved, key)
+		}(key, AsBatchUnit(units[key], p.BatchConcurrency))
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return saved, fmt.Errorf("error saving unit %w", errs)
+	}
+	return saved, nil
+}
+
+var _ protocols.BatchSaveStrategy[any, any] = (*ParallelSaveStrategy[any, any])(nil)
+
+// GetMany reads the keys tier by tier, asking each unit only for the keys the
+// previous units did not return, and backfills each tier that missed with a
+// single batch save.
+func (c *CacheGetStrategy[K, V]) GetMany(ctx context.Context, queries []K, units map[string]protocols.StorageUnit[K, V], targets []string, auxiliary ...any) ([]V, error) {
+	values := make([]V, len(queries))
+
+	if len(auxiliary) < 1 {
+		return values, fmt.Errorf("save function not found")
+	}
+
+	saveFunction, ok := auxiliary[0].(protocols.BatchSaveStrategy[K, V])
+	if !ok {
+		return values, fmt.Errorf("save function check did not work")
+	}
+
+	if len(targets) == 0 {
+		return values, protocols.ErrNoTargets
+	}
+
+	pending := make([]int, len(queries))
+	for index := range pending {
+		pending[index] = index
+	}
+	notExistIn := make(map[string][]int, len(targets))
+	failed := make([]protocols.UnitErrors, len(queries))
+	var skipped []string
+
+	report := protocols.GetReportFromContext(ctx)
+	for tier, target := range targets {
+		if len(pending) == 0 {
+			break
+		}
+
+		if !available(units[target]) {
+			skipped = append(skipped, target)
+			continue
+		}
+
+		unit := AsBatchUnit(units[target], c.BatchConcurrency)
+		found, errs := getMany(ctx, unit, pick(queries, pending))
+
+		next := make([]int, 0, len(pending))
+		for position, index := range pending {
+			err := errs[position]
+			switch {
+			case err == nil:
+				values[index] = found[position]
+				report.Hit(target, tier)
+				continue
+			case errors.Is(err, protocols.ErrNotFound):
+				notExistIn[target] = append(notExistIn[target], index)
+			default:
+				if failed[index] == nil {
+					failed[index] = protocols.UnitErrors{}
+				}
+				failed[index][target] = err
+			}
+			next = append(next, index)
+		}
+		pending = next
+	}
+
+	result := make(protocols.BatchErrors, len(queries))
+	unresolved := make([]bool, len(queries))
+	for _, index := range pending {
+		unresolved[index] = true
+		if len(skipped) > 0 {
+			if failed[index] == nil {
+				failed[index] = protocols.UnitErrors{}
+			}
+			reportSkipped(failed[index], skipped)
+		}
+		if len(failed[index]) == 0 {
+			result[index] = notFound(ctx, targets)
+		} else {
+			result[index] = fmt.Errorf("no unit returned: %w", failed[index])
+		}
+	}
+
+	for _, target := range protocols.WritableTargets(ctx, targets) {
+		var backfill []int
+		for _, index := range notExistIn[target] {
+			if !unresolved[index] {
+				backfill = append(backfill, index)
+			}
+		}
+		if len(backfill) == 0 {
+			continue
+		}
+
+		_, err := saveFunction.SaveMany(ctx, pick(queries, backfill), pick(values, backfill), units, []string{target})
+		if err == nil {
+			report.Backfill(target, len(backfill))
+		} else {
+			report.BackfillFailed(target, err)
+			for _, index := range backfill {
+				result[index] = errors.Join(result[index], fmt.Errorf("err saving to units: %w", err))
+			}
+		}
+	}
+
+	for index, unitErrs := range failed {
+		if !unresolved[index] && len(unitErrs) > 0 {
+			result[index] = errors.Join(result[index], fmt.Errorf("error getting from unit %w", unitErrs))
+		}
+	}
+
+	return values, result.ErrorOrNil()
+}
+
+var _ protocols.BatchGetStrategy[any, any] = (*CacheGetStrategy[any, any])(nil)
+
+func (s *SequentialDeleteStrategy[K, V]) DeleteMany(ctx context.Context, queries []K, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	deleted := make([]string, 0, len(targets))
+	for _, key := range targets {
+		unit := AsBatchUnit(units[key], s.BatchConcurrency)
+		if err := unit.DeleteMany(ctx, queries); err != nil {
+			return deleted, fmt.Errorf("error deleting in unit %w", protocols.UnitErrors{key: err})
+		}
+		deleted = append(deleted, key)
+	}
+	return deleted, nil
+}
+
+var _ protocols.BatchDeleteStrategy[any, any] = (*SequentialDeleteStrategy[any, any])(nil)
+
+func (p *ParallelDeleteStrategy[K, V]) DeleteMany(ctx context.Context, queries []K, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	var wg sync.WaitGroup
+	mu := sync.Mutex{}
+	deleted := make([]string, 0, len(targets))
+	errs := protocols.UnitErrors{}
+
+	for _, key := range targets {
+		wg.Add(1)
+
+		go func(key string, unit protocols.BatchStorageUnit[K, V]) {
+			defer wg.Done()
+
+			err := unit.DeleteMany(ctx, queries)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[key] = err
+				return
+			}
+			deleted = append(deleted, key)
+		}(key, AsBatchUnit(units[key], p.BatchConcurrency))
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return deleted, fmt.Errorf("error deleting in unit %w", errs)
+	}
+	return deleted, nil
+}
+
+var _ protocols.BatchDeleteStrategy[any, any] = (*ParallelDeleteStrategy[any, any])(nil)
+
+func (b *BestEffortDeleteStrategy[K, V]) DeleteMany(ctx context.Context, queries []K, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	deleted := make([]string, 0, len(targets))
+	errs := protocols.UnitErrors{}
+
+	for _, key := range targets {
+		unit := AsBatchUnit(units[key], b.BatchConcurrency)
+		if err := unit.DeleteMany(ctx, queries); err != nil {
+			errs[key] = err
+			continue
+		}
+		deleted = append(deleted, key)
+	}
+
+	if len(errs) > 0 {
+		return deleted, fmt.Errorf("error deleting in unit %w", errs)
+	}
+	return deleted, nil
+}
+
+var _ protocols.BatchDeleteStrategy[any, any] = (*BestEffortDeleteStrategy[any, any])(nil)
@@ -0,0 +1,219 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+type concurrencyUnit struct {
+	unit_test.MemoryUnit[string, string]
+	running atomic.Int32
+	max     atomic.Int32
+}
+
+func (c *concurrencyUnit) Save(ctx context.Context, query string, item string) error {
+	running := c.running.Add(1)
+	defer c.running.Add(-1)
+	for {
+		max := c.max.Load()
+		if running <= max || c.max.CompareAndSwap(max, running) {
+			break
+		}
+	}
+	time.Sleep(time.Millisecond)
+	return nil
+}
+
+var batchMock1 *unit_test.BatchUnitMock
+var batchMock2 *unit_test.BatchUnitMock
+var batchUnits map[string]protocols.StorageUnit[string, string]
+
+func batchSetup() {
+	initialSetup()
+	batchMock1 = unit_test.NewBatchUnitMock()
+	batchMock2 = unit_test.NewBatchUnitMock()
+	batchUnits = map[string]protocols.StorageUnit[string, string]{
+		"mock1": batchMock1,
+		"mock2": batchMock2,
+	}
+}
+
+func TestAsBatchUnit(t *testing.T) {
+
+	t.Run("should return the unit itself when it supports batches", func(t *testing.T) {
+		batchSetup()
+
+		assert.Same(t, batchMock1, AsBatchUnit[string, string](batchMock1, 0))
+	})
+
+	t.Run("should fan out single calls when the unit does not support batches", func(t *testing.T) {
+		batchSetup()
+		ctx := context.Background()
+
+		mock1.On("Save", "a", "va", mock.Anything).Return(nil)
+		mock1.On("Save", "b", "vb", mock.Anything).Return(fmt.Errorf("unit1 error"))
+		mock1.On("Get", "a", mock.Anything).Return("va", nil)
+		mock1.On("Get", "b", mock.Anything).Return("", protocols.ErrNotFound)
+
+		unit := AsBatchUnit[string, string](mock1, 0)
+
+		err := unit.SaveMany(ctx, []string{"a", "b"}, []string{"va", "vb"})
+		var batchErrs protocols.BatchErrors
+		assert.ErrorAs(t, err, &batchErrs)
+		assert.NoError(t, batchErrs[0])
+		assert.EqualError(t, batchErrs[1], "unit1 error")
+
+		values, errs := unit.GetMany(ctx, []string{"a", "b"})
+		assert.Equal(t, []string{"va", ""}, values)
+		assert.NoError(t, errs[0])
+		assert.ErrorIs(t, errs[1], protocols.ErrNotFound)
+
+		mock1.AssertExpectations(t)
+	})
+
+	t.Run("should bound the single calls running at once", func(t *testing.T) {
+		unit := &concurrencyUnit{}
+		queries := make([]string, 20)
+		for c := range queries {
+			queries[c] = fmt.Sprint(c)
+		}
+
+		err := AsBatchUnit[string, string](unit, 3).SaveMany(context.Background(), queries, queries)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, unit.max.Load(), int32(3))
+	})
+}
+
+func TestBatchSave(t *testing.T) {
+
+	t.Run("should save the whole batch in every unit with one call per unit", func(t *testing.T) {
+		batchSetup()
+		ctx := context.Background()
+		strategy := SequentialSaveStrategy[string, string]{}
+
+		batchMock1.On("SaveMany", []string{"a", "b"}, []string{"va", "vb"}, mock.Anything).Return(nil).Once()
+		batchMock2.On("SaveMany", []string{"a", "b"}, []string{"va", "vb"}, mock.Anything).Return(nil).Once()
+
+		saved, err := strategy.SaveMany(ctx, []string{"a", "b"}, []string{"va", "vb"}, batchUnits, targets)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mock1", "mock2"}, saved)
+
+		batchMock1.AssertExpectations(t)
+		batchMock2.AssertExpectations(t)
+	})
+
+	t.Run("should report the units that failed in parallel", func(t *testing.T) {
+		batchSetup()
+		ctx := context.Background()
+		strategy := ParallelSaveStrategy[string, string]{}
+
+		batchMock1.On("SaveMany", []string{"a"}, []string{"va"}, mock.Anything).Return(fmt.Errorf("unit1 error"))
+		batchMock2.On("SaveMany", []string{"a"}, []string{"va"}, mock.Anything).Return(nil)
+
+		_, err := strategy.SaveMany(ctx, []string{"a"}, []string{"va"}, batchUnits, targets)
+
+		var unitErrs protocols.UnitErrors
+		assert.ErrorAs(t, err, &unitErrs)
+		assert.Contains(t, unitErrs.Units(), "mock1")
+	})
+}
+
+func TestBatchCacheGet(t *testing.T) {
+
+	t.Run("should ask each tier only for the missing keys and backfill it in one batch", func(t *testing.T) {
+		batchSetup()
+		ctx := context.Background()
+		strategy := CacheGetStrategy[string, string]{}
+		backfill := SequentialSaveStrategy[string, string]{}
+
+		batchMock1.On("GetMany", []string{"a", "b", "c"}, mock.Anything).Return([]string{"va", "", ""}, []error{nil, protocols.ErrNotFound, protocols.ErrNotFound})
+		batchMock2.On("GetMany", []string{"b", "c"}, mock.Anything).Return([]string{"vb", "vc"}, []error{nil, nil})
+		batchMock1.On("SaveMany", []string{"b", "c"}, []string{"vb", "vc"}, mock.Anything).Return(nil).Once()
+
+		values, err := strategy.GetMany(ctx, []string{"a", "b", "c"}, batchUnits, targets, &backfill)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"va", "vb", "vc"}, values)
+
+		batchMock1.AssertExpectations(t)
+		batchMock2.AssertExpectations(t)
+	})
+
+	t.Run("should report misses and failures per key", func(t *testing.T) {
+		batchSetup()
+		ctx := context.Background()
+		strategy := CacheGetStrategy[string, string]{}
+		backfill := SequentialSaveStrategy[string, string]{}
+
+		batchMock1.On("GetMany", []string{"a", "b"}, mock.Anything).Return([]string{"", ""}, []error{protocols.ErrNotFound, fmt.Errorf("timeout")})
+		batchMock2.On("GetMany", []string{"a", "b"}, mock.Anything).Return([]string{"", ""}, []error{protocols.ErrNotFound, protocols.ErrNotFound})
+
+		_, err := strategy.GetMany(ctx, []string{"a", "b"}, batchUnits, targets, &backfill)
+
+		var batchErrs protocols.BatchErrors
+		assert.ErrorAs(t, err, &batchErrs)
+		assert.ErrorIs(t, batchErrs[0], protocols.ErrNotFound)
+		assert.NotErrorIs(t, batchErrs[1], protocols.ErrNotFound)
+		assert.ErrorContains(t, batchErrs[1], "mock1: timeout")
+
+		batchMock1.AssertNotCalled(t, "SaveMany", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should report an unavailable tier only for the keys no unit returned", func(t *testing.T) {
+		batchSetup()
+		ctx := context.Background()
+		strategy := CacheGetStrategy[string, string]{}
+		backfill := SequentialSaveStrategy[string, string]{}
+		batchUnits["mock1"] = unavailableUnit{mock1}
+
+		batchMock2.On("GetMany", []string{"a", "b"}, mock.Anything).Return([]string{"va", ""}, []error{nil, protocols.ErrNotFound})
+
+		values, err := strategy.GetMany(ctx, []string{"a", "b"}, batchUnits, targets, &backfill)
+
+		var batchErrs protocols.BatchErrors
+		assert.ErrorAs(t, err, &batchErrs)
+		assert.Equal(t, "va", values[0])
+		assert.NoError(t, batchErrs[0])
+		assert.ErrorIs(t, batchErrs[1], protocols.ErrCircuitOpen)
+		assert.NotErrorIs(t, batchErrs[1], protocols.ErrNotFound)
+	})
+}
+
+func TestBatchDelete(t *testing.T) {
+
+	t.Run("should delete the batch in every unit even when one fails", func(t *testing.T) {
+		batchSetup()
+		ctx := context.Background()
+		strategy := BestEffortDeleteStrategy[string, string]{}
+
+		batchMock1.On("DeleteMany", []string{"a", "b"}, mock.Anything).Return(fmt.Errorf("unit1 error"))
+		batchMock2.On("DeleteMany", []string{"a", "b"}, mock.Anything).Return(nil)
+
+		deleted, err := strategy.DeleteMany(ctx, []string{"a", "b"}, batchUnits, targets)
+		assert.ErrorContains(t, err, "error deleting in unit mock1: unit1 error")
+		assert.Equal(t, []string{"mock2"}, deleted)
+	})
+
+	t.Run("should delete the batch in every unit concurrently", func(t *testing.T) {
+		batchSetup()
+		ctx := context.Background()
+		strategy := ParallelDeleteStrategy[string, string]{}
+		var calls sync.WaitGroup
+		calls.Add(2)
+
+		batchMock1.On("DeleteMany", []string{"a"}, mock.Anything).Run(func(mock.Arguments) { calls.Done(); calls.Wait() }).Return(nil)
+		batchMock2.On("DeleteMany", []string{"a"}, mock.Anything).Run(func(mock.Arguments) { calls.Done(); calls.Wait() }).Return(nil)
+
+		deleted, err := strategy.DeleteMany(ctx, []string{"a"}, batchUnits, targets)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"mock1", "mock2"}, deleted)
+	})
+}
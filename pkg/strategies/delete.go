@@ -3,21 +3,92 @@ package strategies
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
 )
 
-type SequentialDeleteStrategy[K any, V any] struct{}
+type SequentialDeleteStrategy[K any, V any] struct {
+	BatchConcurrency int
+}
 
-func (s *SequentialDeleteStrategy[K, V]) Delete(ctx context.Context, query K, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) error {
+func (s *SequentialDeleteStrategy[K, V]) Delete(ctx context.Context, query K, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	deleted := make([]string, 0, len(targets))
 	for _, key := range targets {
 		unit := units[key]
 		err := unit.Delete(ctx, query)
 		if err != nil {
-			return fmt.Errorf("error deleting in unit %v: %v", key, err.Error())
+			return deleted, fmt.Errorf("error deleting in unit %w", protocols.UnitErrors{key: err})
 		}
+		deleted = append(deleted, key)
 	}
-	return nil
+	return deleted, nil
 }
 
 var _ protocols.DeleteStrategy[any, any] = (*SequentialDeleteStrategy[any, any])(nil)
+
+type ParallelDeleteStrategy[K any, V any] struct {
+	BatchConcurrency int
+}
+
+func (p *ParallelDeleteStrategy[K, V]) Delete(ctx context.Context, query K, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	var wg sync.WaitGroup
+	mu := sync.Mutex{}
+	deleted := make([]string, 0, len(targets))
+	errs := protocols.UnitErrors{}
+
+	for _, key := range targets {
+		wg.Add(1)
+
+		go func(key string, unit protocols.StorageUnit[K, V]) {
+			defer wg.Done()
+
+			err := unit.Delete(ctx, query)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs[key] = err
+				return
+			}
+			deleted = append(deleted, key)
+		}(key, units[key])
+	}
+
+	wg.Wait()
+
+	if len(errs) > 0 {
+		return deleted, fmt.Errorf("error deleting in unit %w", errs)
+	}
+
+	return deleted, nil
+}
+
+var _ protocols.DeleteStrategy[any, any] = (*ParallelDeleteStrategy[any, any])(nil)
+
+type BestEffortDeleteStrategy[K any, V any] struct {
+	BatchConcurrency int
+}
+
+func (b *BestEffortDeleteStrategy[K, V]) Delete(ctx context.Context, query K, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	deleted := make([]string, 0, len(targets))
+	errs := protocols.UnitErrors{}
+
+	for _, key := range targets {
+		unit := units[key]
+		err := unit.Delete(ctx, query)
+		if err != nil {
+			errs[key] = err
+			continue
+		}
+		deleted = append(deleted, key)
+	}
+
+	if len(errs) > 0 {
+		return deleted, fmt.Errorf("error deleting in unit %w", errs)
+	}
+
+	return deleted, nil
+}
+
+var _ protocols.DeleteStrategy[any, any] = (*BestEffortDeleteStrategy[any, any])(nil)
@@ -10,12 +10,24 @@ import (
 )
 
 var sequentialDeleteStrategy SequentialDeleteStrategy[string, string]
+var parallelDeleteStrategy ParallelDeleteStrategy[string, string]
+var bestEffortDeleteStrategy BestEffortDeleteStrategy[string, string]
 
 func deleteSequentialSetup() {
 	sequentialDeleteStrategy = SequentialDeleteStrategy[string, string]{}
 	initialSetup()
 }
 
+func deleteParallelSetup() {
+	parallelDeleteStrategy = ParallelDeleteStrategy[string, string]{}
+	initialSetup()
+}
+
+func deleteBestEffortSetup() {
+	bestEffortDeleteStrategy = BestEffortDeleteStrategy[string, string]{}
+	initialSetup()
+}
+
 func TestSequentialDelete(t *testing.T) {
 
 	t.Run("should reach all storage units when none returns error", func(t *testing.T) {
@@ -25,8 +37,9 @@ func TestSequentialDelete(t *testing.T) {
 		mock1.On("Delete", "query", mock.Anything).Return(nil)
 		mock2.On("Delete", "query", mock.Anything).Return(nil)
 
-		err := sequentialDeleteStrategy.Delete(ctx, "query", units, targets)
+		deleted, err := sequentialDeleteStrategy.Delete(ctx, "query", units, targets)
 		assert.NoError(t, err)
+		assert.Equal(t, []string{"mock1", "mock2"}, deleted)
 
 		mock1.AssertNumberOfCalls(t, "Delete", 1)
 		mock2.AssertNumberOfCalls(t, "Delete", 1)
@@ -41,8 +54,9 @@ func TestSequentialDelete(t *testing.T) {
 		ctx := context.Background()
 		mock1.On("Delete", "query", mock.Anything).Return(fmt.Errorf("mock1 error"))
 
-		err := sequentialDeleteStrategy.Delete(ctx, "query", units, targets)
+		deleted, err := sequentialDeleteStrategy.Delete(ctx, "query", units, targets)
 		assert.ErrorContains(t, err, "mock1 error")
+		assert.Empty(t, deleted)
 
 		mock1.AssertNumberOfCalls(t, "Delete", 1)
 		mock2.AssertNumberOfCalls(t, "Delete", 0)
@@ -51,3 +65,69 @@ func TestSequentialDelete(t *testing.T) {
 		mock2.AssertExpectations(t)
 	})
 }
+
+func TestParallelDelete(t *testing.T) {
+
+	t.Run("should reach all storage units when none returns error", func(t *testing.T) {
+		deleteParallelSetup()
+		ctx := context.Background()
+
+		mock1.On("Delete", "query", mock.Anything).Return(nil)
+		mock2.On("Delete", "query", mock.Anything).Return(nil)
+
+		deleted, err := parallelDeleteStrategy.Delete(ctx, "query", units, targets)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"mock1", "mock2"}, deleted)
+
+		mock1.AssertExpectations(t)
+		mock2.AssertExpectations(t)
+	})
+
+	t.Run("should reach all storage units and return the deleted ones when some unit fails", func(t *testing.T) {
+		deleteParallelSetup()
+		ctx := context.Background()
+
+		mock1.On("Delete", "query", mock.Anything).Return(fmt.Errorf("mock1 error"))
+		mock2.On("Delete", "query", mock.Anything).Return(nil)
+
+		deleted, err := parallelDeleteStrategy.Delete(ctx, "query", units, targets)
+		assert.ErrorContains(t, err, "error deleting in unit mock1: mock1 error")
+		assert.ElementsMatch(t, []string{"mock2"}, deleted)
+
+		mock1.AssertExpectations(t)
+		mock2.AssertExpectations(t)
+	})
+}
+
+func TestBestEffortDelete(t *testing.T) {
+
+	t.Run("should reach all storage units when none returns error", func(t *testing.T) {
+		deleteBestEffortSetup()
+		ctx := context.Background()
+
+		mock1.On("Delete", "query", mock.Anything).Return(nil)
+		mock2.On("Delete", "query", mock.Anything).Return(nil)
+
+		deleted, err := bestEffortDeleteStrategy.Delete(ctx, "query", units, targets)
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"mock1", "mock2"}, deleted)
+
+		mock1.AssertExpectations(t)
+		mock2.AssertExpectations(t)
+	})
+
+	t.Run("should keep deleting after a unit fails and report every failure", func(t *testing.T) {
+		deleteBestEffortSetup()
+		ctx := context.Background()
+
+		mock1.On("Delete", "query", mock.Anything).Return(fmt.Errorf("mock1 error"))
+		mock2.On("Delete", "query", mock.Anything).Return(nil)
+
+		deleted, err := bestEffortDeleteStrategy.Delete(ctx, "query", units, targets)
+		assert.ErrorContains(t, err, "error deleting in unit mock1: mock1 error")
+		assert.Equal(t, []string{"mock2"}, deleted)
+
+		mock1.AssertNumberOfCalls(t, "Delete", 1)
+		mock2.AssertNumberOfCalls(t, "Delete", 1)
+	})
+}
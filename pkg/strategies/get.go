@@ -2,17 +2,47 @@ package strategies
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"reflect"
+	"slices"
+	"sort"
+	"sync"
+	"time"
 
 	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
 )
 
-type CacheGetStrategy[K any, V any] struct{}
+type unitGetResult[V any] struct {
+	key   string
+	value V
+	err   error
+}
 
-func (c *CacheGetStrategy[K, V]) Get(ctx context.Context, query K, units map[string]protocols.StorageUnit[K, V], targets []string, auxiliary ...any) (value V, returnErr error) {
-	var notExistIn []string
+// available reports whether the unit, or a unit it decorates, expects calls to
+// succeed right now.
+func available[K any, V any](unit protocols.StorageUnit[K, V]) bool {
+	for unit != nil {
+		if reporter, ok := unit.(protocols.AvailabilityReporter); ok {
+			return reporter.Available()
+		}
+		wrapper, ok := unit.(protocols.UnitWrapper[K, V])
+		if !ok {
+			return true
+		}
+		unit = wrapper.Unwrap()
+	}
+	return true
+}
 
-	if len(auxiliary) != 1 {
+type CacheGetStrategy[K any, V any] struct {
+	BatchConcurrency int
+}
+
+func (c *CacheGetStrategy[K, V]) Get(ctx context.Context, query K, units map[string]protocols.StorageUnit[K, V], targets []string, auxiliary ...any) (V, error) {
+	var value V
+
+	if len(auxiliary) < 1 {
 		return value, fmt.Errorf("save function not found")
 	}
 
@@ -21,36 +51,432 @@ func (c *CacheGetStrategy[K, V]) Get(ctx context.Context, query K, units map[str
 		return value, fmt.Errorf("save function check did not work")
 	}
 
-	defer func() {
-		returnErr = c.addMissingElements(ctx, query, value, targets, units, notExistIn, saveFunction)
-	}()
+	if len(targets) == 0 {
+		return value, protocols.ErrNoTargets
+	}
+
+	var notExistIn, skipped []string
+	failed := protocols.UnitErrors{}
 
-	for _, target := range targets {
+	for tier, target := range targets {
 		unit := units[target]
-		value, err := unit.Get(ctx, query)
+		if !available(unit) {
+			skipped = append(skipped, target)
+			continue
+		}
+		found, err := unit.Get(ctx, query)
 		if err == nil {
-			return value, nil
+			protocols.GetReportFromContext(ctx).Hit(target, tier)
+			return found, c.addMissingElements(ctx, query, found, units, notExistIn, failed, saveFunction)
+		}
+		if errors.Is(err, protocols.ErrNotFound) {
+			notExistIn = append(notExistIn, target)
+			continue
 		}
-		notExistIn = append(notExistIn, target)
+		failed[target] = err
 	}
 
-	return value, returnErr
+	reportSkipped(failed, skipped)
+	if len(failed) == 0 {
+		return value, notFound(ctx, targets)
+	}
+	return value, fmt.Errorf("no unit returned: %w", failed)
 }
 
-func (c *CacheGetStrategy[K, V]) addMissingElements(ctx context.Context, query K, value V, orders []string, units map[string]protocols.StorageUnit[K, V], missing []string, saveFunction protocols.SaveStrategy[K, V]) error {
-	if len(missing) == len(orders) {
-		err := fmt.Errorf("no unit returned")
-		return err
-	}
+func (c *CacheGetStrategy[K, V]) addMissingElements(ctx context.Context, query K, value V, units map[string]protocols.StorageUnit[K, V], missing []string, failed protocols.UnitErrors, saveFunction protocols.SaveStrategy[K, V]) error {
+	var errs []error
 
+	missing = protocols.WritableTargets(ctx, missing)
 	if len(missing) > 0 {
-		_, err := saveFunction.Save(ctx, query, value, units, missing)
+		saved, err := saveFunction.Save(ctx, query, value, units, missing)
+		report := protocols.GetReportFromContext(ctx)
+		for _, unit := range saved {
+			report.Backfill(unit, 1)
+		}
 		if err != nil {
-			return fmt.Errorf("err saving to units: %v ", err.Error())
+			reportFailedBackfills(report, missing, saved, err)
+			errs = append(errs, fmt.Errorf("err saving to units: %w", err))
+		}
+	}
+
+	if len(failed) > 0 {
+		errs = append(errs, fmt.Errorf("error getting from unit %w", failed))
+	}
+
+	return errors.Join(errs...)
+}
 
+// reportFailedBackfills records the units of missing that were not saved,
+// with their own error when err tells it.
+func reportFailedBackfills(report *protocols.GetReport, missing, saved []string, err error) {
+	var unitErrs protocols.UnitErrors
+	errors.As(err, &unitErrs)
+
+	for _, unit := range missing {
+		if slices.Contains(saved, unit) {
+			continue
+		}
+		if unitErr, ok := unitErrs[unit]; ok {
+			report.BackfillFailed(unit, unitErr)
+		} else {
+			report.BackfillFailed(unit, err)
 		}
 	}
-	return nil
 }
 
 var _ protocols.GetStrategy[any, any] = (*CacheGetStrategy[any, any])(nil)
+
+// reportSkipped records the units skipped as unavailable among the failed
+// ones. Strategies only report them when no unit returned the item, since the
+// skipped units could have held it.
+func reportSkipped(failed protocols.UnitErrors, skipped []string) {
+	for _, unit := range skipped {
+		failed[unit] = protocols.ErrCircuitOpen
+	}
+}
+
+// notFound returns the error of a get that found the item in no unit and
+// failed in none: a clean miss when an authoritative unit missed it, and
+// protocols.ErrUnconfirmedMiss when only caches and volatile units did.
+func notFound(ctx context.Context, missed []string) error {
+	if protocols.AuthoritativeMiss(ctx, missed) {
+		return fmt.Errorf("no unit returned: %w", protocols.ErrNotFound)
+	}
+	return fmt.Errorf("no unit returned: %w", protocols.ErrUnconfirmedMiss)
+}
+
+type RaceGetStrategy[K any, V any] struct{}
+
+func (r *RaceGetStrategy[K, V]) Get(ctx context.Context, query K, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) (V, error) {
+	var value V
+	if len(targets) == 0 {
+		return value, protocols.ErrNoTargets
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var skipped []string
+	failed := protocols.UnitErrors{}
+	resultCh := make(chan unitGetResult[V], len(targets))
+	tiers := make(map[string]int, len(targets))
+	queried := 0
+	for tier, key := range targets {
+		tiers[key] = tier
+		if !available(units[key]) {
+			skipped = append(skipped, key)
+			continue
+		}
+		queried++
+		go func(key string, unit protocols.StorageUnit[K, V]) {
+			value, err := unit.Get(ctx, query)
+			resultCh <- unitGetResult[V]{key: key, value: value, err: err}
+		}(key, units[key])
+	}
+
+	for c := 0; c < queried; c++ {
+		result := <-resultCh
+		if result.err == nil {
+			protocols.GetReportFromContext(ctx).Hit(result.key, tiers[result.key])
+			return result.value, nil
+		}
+		if !errors.Is(result.err, protocols.ErrNotFound) {
+			failed[result.key] = result.err
+		}
+	}
+
+	reportSkipped(failed, skipped)
+	if len(failed) == 0 {
+		return value, notFound(ctx, targets)
+	}
+	return value, fmt.Errorf("no unit returned: %w", failed)
+}
+
+var _ protocols.GetStrategy[any, any] = (*RaceGetStrategy[any, any])(nil)
+
+// ValueResolver picks the value to return from the values read from each unit.
+type ValueResolver[V any] func(values map[string]V) (V, error)
+
+// MajorityResolver returns the value held by most units, breaking ties by the
+// lexical order of the unit names.
+func MajorityResolver[V any](values map[string]V) (V, error) {
+	var winner V
+	if len(values) == 0 {
+		return winner, protocols.ErrNotFound
+	}
+
+	names := make([]string, 0, len(values))
+	for name := range values {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	best := 0
+	for _, name := range names {
+		votes := 0
+		for _, other := range names {
+			if reflect.DeepEqual(values[name], values[other]) {
+				votes++
+			}
+		}
+		if votes > best {
+			best = votes
+			winner = values[name]
+		}
+	}
+	return winner, nil
+}
+
+type QuorumGetStrategy[K any, V any] struct {
+	Resolver ValueResolver[V]
+	OnRepair func(query K, err error)
+}
+
+// Get reads every target concurrently until the read quorum, passed as an int
+// after the save strategy in auxiliary, answered with a value or a clean miss.
+// The value is picked by the Resolver (MajorityResolver when nil) and the units
+// that missed it or hold a different value are rewritten in the background.
+// OnRepair, when set, is called with the outcome of each background rewrite.
+func (q *QuorumGetStrategy[K, V]) Get(ctx context.Context, query K, units map[string]protocols.StorageUnit[K, V], targets []string, auxiliary ...any) (V, error) {
+	var value V
+
+	if len(auxiliary) < 1 {
+		return value, fmt.Errorf("save function not found")
+	}
+
+	saveFunction, ok := auxiliary[0].(protocols.SaveStrategy[K, V])
+	if !ok {
+		return value, fmt.Errorf("save function check did not work")
+	}
+
+	if len(targets) == 0 {
+		return value, protocols.ErrNoTargets
+	}
+
+	quorum := len(targets)/2 + 1
+	if len(auxiliary) > 1 {
+		if n, ok := auxiliary[1].(int); ok && n > 0 {
+			quorum = n
+		}
+	}
+
+	if quorum > len(targets) {
+		return value, fmt.Errorf("%w: read quorum %v is greater than the %v targets", protocols.ErrQuorumNotReached, quorum, len(targets))
+	}
+
+	readCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	resultCh := make(chan unitGetResult[V], len(targets))
+	for _, key := range targets {
+		go func(key string, unit protocols.StorageUnit[K, V]) {
+			value, err := unit.Get(readCtx, query)
+			resultCh <- unitGetResult[V]{key: key, value: value, err: err}
+		}(key, units[key])
+	}
+
+	values := make(map[string]V, quorum)
+	var missing []string
+	failed := protocols.UnitErrors{}
+	for len(values)+len(missing) < quorum {
+		result := <-resultCh
+		switch {
+		case result.err == nil:
+			values[result.key] = result.value
+		case errors.Is(result.err, protocols.ErrNotFound):
+			missing = append(missing, result.key)
+		default:
+			failed[result.key] = result.err
+		}
+
+		if len(failed) > len(targets)-quorum {
+			return value, fmt.Errorf("%w: %v of %v answered, error getting from unit %w", protocols.ErrQuorumNotReached, len(values)+len(missing), quorum, failed)
+		}
+	}
+
+	if len(values) == 0 {
+		return value, notFound(ctx, missing)
+	}
+
+	resolver := q.Resolver
+	if resolver == nil {
+		resolver = MajorityResolver[V]
+	}
+
+	value, err := resolver(values)
+	if err != nil {
+		return value, fmt.Errorf("error resolving value: %w", err)
+	}
+
+	stale := protocols.WritableTargets(ctx, missing)
+	for key, unitValue := range values {
+		if !reflect.DeepEqual(unitValue, value) && !protocols.UnitOptionsFromContext(ctx)[key].ReadOnly {
+			stale = append(stale, key)
+		}
+	}
+
+	if len(stale) > 0 {
+		sort.Strings(stale)
+		onRepair := q.OnRepair
+		go func() {
+			_, err := saveFunction.Save(context.WithoutCancel(ctx), query, value, units, stale)
+			if onRepair != nil {
+				onRepair(query, err)
+			}
+		}()
+	}
+
+	return value, nil
+}
+
+var _ protocols.GetStrategy[any, any] = (*QuorumGetStrategy[any, any])(nil)
+
+// StaleWhileRevalidateGetStrategy reads the targets in order like the Cache
+// strategy and returns the first value found right away. When that value is
+// stale and came from a target before the source of truth, the first unit with
+// protocols.PrimaryRole or else the last target, the value is read again from
+// the source in the background and saved to every target before it, or
+// deleted from them if the source no longer has it.
+//
+// A value is stale when its unit marks it so, or when it was written more than
+// MaxAge ago. Values from units that do not implement
+// protocols.MetadataStorageUnit are never stale. OnRevalidate, when set, is
+// called with the outcome of each background refresh.
+//
+// At most one refresh of a key runs at a time: a Get that finds a stale value
+// while the key is being refreshed returns it without starting another. Key
+// tells which keys are the same, the keys printed the same with fmt when nil.
+type StaleWhileRevalidateGetStrategy[K any, V any] struct {
+	MaxAge       time.Duration
+	OnRevalidate func(query K, err error)
+	Key          protocols.KeyFunc[K]
+
+	mu         sync.Mutex
+	refreshing map[string]bool
+}
+
+func (s *StaleWhileRevalidateGetStrategy[K, V]) Get(ctx context.Context, query K, units map[string]protocols.StorageUnit[K, V], targets []string, auxiliary ...any) (V, error) {
+	var value V
+
+	if len(auxiliary) < 1 {
+		return value, fmt.Errorf("save function not found")
+	}
+
+	saveFunction, ok := auxiliary[0].(protocols.SaveStrategy[K, V])
+	if !ok {
+		return value, fmt.Errorf("save function check did not work")
+	}
+
+	if len(targets) == 0 {
+		return value, protocols.ErrNoTargets
+	}
+
+	var notExistIn, skipped []string
+	failed := protocols.UnitErrors{}
+	source := slices.Index(targets, protocols.SourceTargets(ctx, targets)[0])
+
+	for tier, target := range targets {
+		unit := units[target]
+		if !available(unit) {
+			skipped = append(skipped, target)
+			continue
+		}
+		found, stale, err := s.get(ctx, unit, query)
+		if err == nil {
+			protocols.GetReportFromContext(ctx).Hit(target, tier)
+			err = (&CacheGetStrategy[K, V]{}).addMissingElements(ctx, query, found, units, notExistIn, failed, saveFunction)
+			if stale && tier < source {
+				s.startRevalidation(context.WithoutCancel(ctx), query, units, targets[source], protocols.WritableTargets(ctx, targets[:source]), saveFunction)
+			}
+			return found, err
+		}
+		if errors.Is(err, protocols.ErrNotFound) {
+			notExistIn = append(notExistIn, target)
+			continue
+		}
+		failed[target] = err
+	}
+
+	reportSkipped(failed, skipped)
+	if len(failed) == 0 {
+		return value, notFound(ctx, targets)
+	}
+	return value, fmt.Errorf("no unit returned: %w", failed)
+}
+
+// get reads query from unit and tells whether the value is stale.
+func (s *StaleWhileRevalidateGetStrategy[K, V]) get(ctx context.Context, unit protocols.StorageUnit[K, V], query K) (V, bool, error) {
+	metadataUnit, ok := unit.(protocols.MetadataStorageUnit[K, V])
+	if !ok || !reportsMetadata(unit) {
+		value, err := unit.Get(ctx, query)
+		return value, false, err
+	}
+
+	value, metadata, err := metadataUnit.GetWithMetadata(ctx, query)
+	stale := metadata.Stale || (s.MaxAge > 0 && !metadata.WrittenAt.IsZero() && time.Since(metadata.WrittenAt) > s.MaxAge)
+	return value, stale, err
+}
+
+// startRevalidation refreshes query in the background, unless a refresh of it
+// is already running.
+func (s *StaleWhileRevalidateGetStrategy[K, V]) startRevalidation(ctx context.Context, query K, units map[string]protocols.StorageUnit[K, V], source string, faster []string, saveFunction protocols.SaveStrategy[K, V]) {
+	key := fmt.Sprint(query)
+	if s.Key != nil {
+		key = s.Key(query)
+	}
+
+	s.mu.Lock()
+	if s.refreshing[key] {
+		s.mu.Unlock()
+		return
+	}
+	if s.refreshing == nil {
+		s.refreshing = map[string]bool{}
+	}
+	s.refreshing[key] = true
+	s.mu.Unlock()
+
+	go func() {
+		err := s.revalidate(ctx, query, units, source, faster, saveFunction)
+		s.mu.Lock()
+		delete(s.refreshing, key)
+		s.mu.Unlock()
+		if s.OnRevalidate != nil {
+			s.OnRevalidate(query, err)
+		}
+	}()
+}
+
+// revalidate reads query from source and saves it to faster, or deletes it
+// from them if source no longer has it.
+func (s *StaleWhileRevalidateGetStrategy[K, V]) revalidate(ctx context.Context, query K, units map[string]protocols.StorageUnit[K, V], source string, faster []string, saveFunction protocols.SaveStrategy[K, V]) error {
+	value, err := units[source].Get(ctx, query)
+	switch {
+	case err == nil:
+		_, err = saveFunction.Save(ctx, query, value, units, faster)
+	case errors.Is(err, protocols.ErrNotFound):
+		failed := protocols.UnitErrors{}
+		for _, target := range faster {
+			if err := units[target].Delete(ctx, query); err != nil {
+				failed[target] = err
+			}
+		}
+		err = failed.ErrorOrNil()
+	}
+	return err
+}
+
+// reportsMetadata reports whether the unit behind the decorators implements
+// protocols.MetadataStorageUnit.
+func reportsMetadata[K any, V any](unit protocols.StorageUnit[K, V]) bool {
+	for {
+		wrapper, ok := unit.(protocols.UnitWrapper[K, V])
+		if !ok {
+			_, ok := unit.(protocols.MetadataStorageUnit[K, V])
+			return ok
+		}
+		unit = wrapper.Unwrap()
+	}
+}
+
+var _ protocols.GetStrategy[any, any] = (*StaleWhileRevalidateGetStrategy[any, any])(nil)
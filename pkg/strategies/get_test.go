@@ -3,14 +3,28 @@ package strategies
 import (
 	"context"
 	"fmt"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
 	strategies_mock "github.com/joaogabriel01/storage-orchestrator/pkg/strategies/test"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
+type unavailableUnit struct {
+	*unit_test.UnitMock
+}
+
+func (u unavailableUnit) Available() bool {
+	return false
+}
+
 var cacheGetStrategy CacheGetStrategy[string, string]
+var raceGetStrategy RaceGetStrategy[string, string]
+var quorumGetStrategy QuorumGetStrategy[string, string]
 var saveMock *strategies_mock.MockSaveStrategy
 
 func cacheGetSetup() {
@@ -19,6 +33,39 @@ func cacheGetSetup() {
 	initialSetup()
 }
 
+func raceGetSetup() {
+	raceGetStrategy = RaceGetStrategy[string, string]{}
+	initialSetup()
+}
+
+func quorumGetSetup() {
+	quorumGetStrategy = QuorumGetStrategy[string, string]{}
+	saveMock = &strategies_mock.MockSaveStrategy{}
+	initialSetup()
+	mock3 = unit_test.NewUnitMock()
+	units["mock3"] = mock3
+	targets = append(targets, "mock3")
+}
+
+var staleWhileRevalidateGetStrategy *StaleWhileRevalidateGetStrategy[string, string]
+var staleCache *unit_test.MemoryUnit[string, string]
+var staleSource *unit_test.MemoryUnit[string, string]
+var staleRevalidated chan error
+
+func staleWhileRevalidateGetSetup() {
+	staleCache = unit_test.NewMemoryUnit[string, string]()
+	staleSource = unit_test.NewMemoryUnit[string, string]()
+	units = map[string]protocols.StorageUnit[string, string]{"cache": staleCache, "source": staleSource}
+	revalidated := make(chan error, 1)
+	staleRevalidated = revalidated
+	staleWhileRevalidateGetStrategy = &StaleWhileRevalidateGetStrategy[string, string]{
+		MaxAge: time.Minute,
+		OnRevalidate: func(query string, err error) {
+			revalidated <- err
+		},
+	}
+}
+
 func TestGet(t *testing.T) {
 
 	t.Run("should return error when the save function is not passed", func(t *testing.T) {
@@ -60,7 +107,7 @@ func TestGet(t *testing.T) {
 		ctx := context.Background()
 		saveMock.On("Save", mock.Anything, "query", "worked", units, []string{"mock1"}, mock.Anything).Return([]string{"mock1"}, nil)
 
-		mock1.On("Get", "query", mock.Anything).Return("", fmt.Errorf("value not found"))
+		mock1.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
 
 		mock2.On("Get", "query", mock.Anything).Return("worked", nil)
 
@@ -77,14 +124,15 @@ func TestGet(t *testing.T) {
 		cacheGetSetup()
 		ctx := context.Background()
 
-		mock1.On("Get", "query", mock.Anything).Return("", fmt.Errorf("value not found"))
+		mock1.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
 
-		mock2.On("Get", "query", mock.Anything).Return("", fmt.Errorf("value not found"))
+		mock2.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
 
 		value, err := cacheGetStrategy.Get(ctx, "query", units, targets, saveMock)
 
 		assert.Equal(t, "", value)
 		assert.ErrorContains(t, err, "no unit returned")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
 
 		mock1.AssertExpectations(t)
 		mock2.AssertExpectations(t)
@@ -95,7 +143,7 @@ func TestGet(t *testing.T) {
 		ctx := context.Background()
 		saveMock.On("Save", mock.Anything, "query", "worked", units, []string{"mock1"}, mock.Anything).Return([]string{}, fmt.Errorf("didnt'save mock1"))
 
-		mock1.On("Get", "query", mock.Anything).Return("", fmt.Errorf("value not found"))
+		mock1.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
 
 		mock2.On("Get", "query", mock.Anything).Return("worked", nil)
 
@@ -108,4 +156,510 @@ func TestGet(t *testing.T) {
 		mock2.AssertExpectations(t)
 	})
 
+	t.Run("should not backfill a unit that failed and should surface its error with the value", func(t *testing.T) {
+		cacheGetSetup()
+		ctx := context.Background()
+
+		mock1.On("Get", "query", mock.Anything).Return("", fmt.Errorf("timeout"))
+		mock2.On("Get", "query", mock.Anything).Return("worked", nil)
+
+		value, err := cacheGetStrategy.Get(ctx, "query", units, targets, saveMock)
+
+		assert.Equal(t, "worked", value)
+		assert.ErrorContains(t, err, "error getting from unit mock1: timeout")
+		assert.NotErrorIs(t, err, protocols.ErrNotFound)
+
+		saveMock.AssertNotCalled(t, "Save")
+		mock1.AssertExpectations(t)
+		mock2.AssertExpectations(t)
+	})
+
+	t.Run("should backfill only the units that missed when another unit failed", func(t *testing.T) {
+		cacheGetSetup()
+		ctx := context.Background()
+		mock3 := unit_test.NewUnitMock()
+		units["mock3"] = mock3
+
+		saveMock.On("Save", mock.Anything, "query", "worked", units, []string{"mock2"}, mock.Anything).Return([]string{"mock2"}, nil)
+		mock1.On("Get", "query", mock.Anything).Return("", fmt.Errorf("timeout"))
+		mock2.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		mock3.On("Get", "query", mock.Anything).Return("worked", nil)
+
+		value, err := cacheGetStrategy.Get(ctx, "query", units, []string{"mock1", "mock2", "mock3"}, saveMock)
+
+		assert.Equal(t, "worked", value)
+
+		var unitErrs protocols.UnitErrors
+		assert.ErrorAs(t, err, &unitErrs)
+		assert.Equal(t, []string{"mock1"}, unitErrs.Units())
+
+		saveMock.AssertExpectations(t)
+	})
+
+	t.Run("should not report a miss when some unit failed", func(t *testing.T) {
+		cacheGetSetup()
+		ctx := context.Background()
+
+		mock1.On("Get", "query", mock.Anything).Return("", fmt.Errorf("timeout"))
+		mock2.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+
+		value, err := cacheGetStrategy.Get(ctx, "query", units, targets, saveMock)
+
+		assert.Equal(t, "", value)
+		assert.NotErrorIs(t, err, protocols.ErrNotFound)
+
+		var unitErrs protocols.UnitErrors
+		assert.ErrorAs(t, err, &unitErrs)
+		assert.Equal(t, []string{"mock1"}, unitErrs.Units())
+
+		saveMock.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("should report the tier of the hit and the units backfilled", func(t *testing.T) {
+		cacheGetSetup()
+		report := &protocols.GetReport{}
+		ctx := protocols.ContextWithGetReport(context.Background(), report)
+		saveMock.On("Save", mock.Anything, "query", "worked", units, []string{"mock1"}, mock.Anything).Return([]string{"mock1"}, nil)
+
+		mock1.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		mock2.On("Get", "query", mock.Anything).Return("worked", nil)
+
+		_, err := cacheGetStrategy.Get(ctx, "query", units, targets, saveMock)
+
+		assert.NoError(t, err)
+		assert.Equal(t, []protocols.GetHit{{Unit: "mock2", Tier: 1}}, report.Hits())
+		assert.Equal(t, map[string]int{"mock1": 1}, report.Backfills())
+	})
+
+}
+
+func TestGetSkipsUnavailableUnits(t *testing.T) {
+
+	t.Run("should skip an unavailable tier without backfilling it", func(t *testing.T) {
+		cacheGetSetup()
+		ctx := context.Background()
+		units["mock1"] = unavailableUnit{mock1}
+
+		mock2.On("Get", "query", mock.Anything).Return("worked", nil)
+
+		value, err := cacheGetStrategy.Get(ctx, "query", units, targets, saveMock)
+
+		assert.Equal(t, "worked", value)
+		assert.NoError(t, err)
+		mock1.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+		saveMock.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("should report an unavailable tier only when no unit returned the item", func(t *testing.T) {
+		cacheGetSetup()
+		ctx := context.Background()
+		units["mock1"] = unavailableUnit{mock1}
+
+		mock2.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+
+		_, err := cacheGetStrategy.Get(ctx, "query", units, targets, saveMock)
+
+		assert.ErrorIs(t, err, protocols.ErrCircuitOpen)
+		assert.NotErrorIs(t, err, protocols.ErrNotFound)
+	})
+
+	t.Run("should race only the available units", func(t *testing.T) {
+		raceGetSetup()
+		ctx := context.Background()
+		units["mock1"] = unavailableUnit{mock1}
+
+		mock2.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+
+		_, err := raceGetStrategy.Get(ctx, "query", units, targets)
+
+		assert.ErrorIs(t, err, protocols.ErrCircuitOpen)
+		assert.NotErrorIs(t, err, protocols.ErrNotFound)
+		mock1.AssertNotCalled(t, "Get", mock.Anything, mock.Anything)
+	})
+}
+
+func TestGetHonoursUnitOptions(t *testing.T) {
+
+	t.Run("should not report a clean miss when only caches missed", func(t *testing.T) {
+		cacheGetSetup()
+		ctx := protocols.ContextWithUnitOptions(context.Background(), map[string]protocols.UnitOptions{
+			"mock1": {Role: protocols.CacheRole},
+			"mock2": {Durability: protocols.Volatile},
+		})
+
+		mock1.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		mock2.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+
+		_, err := cacheGetStrategy.Get(ctx, "query", units, targets, saveMock)
+		assert.ErrorIs(t, err, protocols.ErrUnconfirmedMiss)
+		assert.NotErrorIs(t, err, protocols.ErrNotFound)
+
+		_, err = raceGetStrategy.Get(ctx, "query", units, targets)
+		assert.ErrorIs(t, err, protocols.ErrUnconfirmedMiss)
+	})
+
+	t.Run("should report a clean miss when an authoritative unit missed", func(t *testing.T) {
+		cacheGetSetup()
+		ctx := protocols.ContextWithUnitOptions(context.Background(), map[string]protocols.UnitOptions{
+			"mock1": {Role: protocols.CacheRole},
+		})
+
+		mock1.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		mock2.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+
+		_, err := cacheGetStrategy.Get(ctx, "query", units, targets, saveMock)
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+	})
+
+	t.Run("should not backfill a read-only unit", func(t *testing.T) {
+		cacheGetSetup()
+		ctx := protocols.ContextWithUnitOptions(context.Background(), map[string]protocols.UnitOptions{
+			"mock1": {ReadOnly: true},
+		})
+
+		mock1.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		mock2.On("Get", "query", mock.Anything).Return("worked", nil)
+
+		value, err := cacheGetStrategy.Get(ctx, "query", units, targets, saveMock)
+		assert.NoError(t, err)
+		assert.Equal(t, "worked", value)
+		saveMock.AssertNotCalled(t, "Save")
+	})
+}
+
+func TestRaceGet(t *testing.T) {
+
+	t.Run("should return the value of the first unit that answers", func(t *testing.T) {
+		raceGetSetup()
+		ctx := context.Background()
+		canceled := make(chan struct{})
+
+		mock1.On("Get", "query", mock.Anything).Return("worked", nil)
+		mock2.On("Get", "query", mock.Anything).Run(func(args mock.Arguments) {
+			<-args.Get(1).(context.Context).Done()
+			close(canceled)
+		}).Return("", context.Canceled)
+
+		value, err := raceGetStrategy.Get(ctx, "query", units, targets)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "worked", value)
+
+		select {
+		case <-canceled:
+		case <-time.After(time.Second):
+			t.Fatal("the slower unit was not canceled")
+		}
+	})
+
+	t.Run("should return a valid value even when some unit fails", func(t *testing.T) {
+		raceGetSetup()
+		ctx := context.Background()
+
+		mock1.On("Get", "query", mock.Anything).Return("", fmt.Errorf("value not found"))
+		mock2.On("Get", "query", mock.Anything).Return("worked", nil)
+
+		value, err := raceGetStrategy.Get(ctx, "query", units, targets)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "worked", value)
+
+		mock2.AssertExpectations(t)
+	})
+
+	t.Run("should return an aggregated error when all units fail", func(t *testing.T) {
+		raceGetSetup()
+		ctx := context.Background()
+
+		mock1.On("Get", "query", mock.Anything).Return("", fmt.Errorf("mock1 error"))
+		mock2.On("Get", "query", mock.Anything).Return("", fmt.Errorf("mock2 error"))
+
+		value, err := raceGetStrategy.Get(ctx, "query", units, targets)
+
+		assert.Equal(t, "", value)
+		assert.ErrorContains(t, err, "no unit returned")
+
+		var unitErrs protocols.UnitErrors
+		assert.ErrorAs(t, err, &unitErrs)
+		assert.Equal(t, []string{"mock1", "mock2"}, unitErrs.Units())
+		assert.EqualError(t, unitErrs["mock1"], "mock1 error")
+		assert.EqualError(t, unitErrs["mock2"], "mock2 error")
+
+		mock1.AssertExpectations(t)
+		mock2.AssertExpectations(t)
+	})
+
+	t.Run("should report a miss when every unit missed", func(t *testing.T) {
+		raceGetSetup()
+		ctx := context.Background()
+
+		mock1.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		mock2.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+
+		_, err := raceGetStrategy.Get(ctx, "query", units, targets)
+
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+	})
+
+	t.Run("should return error when there are no targets", func(t *testing.T) {
+		raceGetSetup()
+		ctx := context.Background()
+
+		_, err := raceGetStrategy.Get(ctx, "query", units, []string{})
+
+		assert.ErrorIs(t, err, protocols.ErrNoTargets)
+	})
+}
+
+func TestQuorumGet(t *testing.T) {
+
+	t.Run("should return error when the save function is not passed", func(t *testing.T) {
+		quorumGetSetup()
+		ctx := context.Background()
+
+		_, err := quorumGetStrategy.Get(ctx, "query", units, targets)
+
+		assert.ErrorContains(t, err, "save function not found")
+	})
+
+	t.Run("should return the value when every unit agrees and not repair anything", func(t *testing.T) {
+		quorumGetSetup()
+		ctx := context.Background()
+
+		mock1.On("Get", "query", mock.Anything).Return("worked", nil)
+		mock2.On("Get", "query", mock.Anything).Return("worked", nil)
+		mock3.On("Get", "query", mock.Anything).Return("worked", nil)
+
+		value, err := quorumGetStrategy.Get(ctx, "query", units, targets, saveMock, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "worked", value)
+		saveMock.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("should return the majority value and repair the divergent unit", func(t *testing.T) {
+		quorumGetSetup()
+		ctx := context.Background()
+		repaired := make(chan struct{})
+
+		mock1.On("Get", "query", mock.Anything).Return("stale", nil)
+		mock2.On("Get", "query", mock.Anything).Return("worked", nil)
+		mock3.On("Get", "query", mock.Anything).Return("worked", nil)
+		saveMock.On("Save", mock.Anything, "query", "worked", units, []string{"mock1"}, mock.Anything).Run(func(mock.Arguments) {
+			close(repaired)
+		}).Return([]string{"mock1"}, nil)
+
+		value, err := quorumGetStrategy.Get(ctx, "query", units, targets, saveMock, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "worked", value)
+
+		select {
+		case <-repaired:
+		case <-time.After(time.Second):
+			t.Fatal("the divergent unit was not repaired")
+		}
+	})
+
+	t.Run("should repair the units that missed", func(t *testing.T) {
+		quorumGetSetup()
+		ctx := context.Background()
+		repaired := make(chan struct{})
+
+		mock1.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		mock2.On("Get", "query", mock.Anything).Return("worked", nil)
+		mock3.On("Get", "query", mock.Anything).Return("worked", nil)
+		saveMock.On("Save", mock.Anything, "query", "worked", units, []string{"mock1"}, mock.Anything).Run(func(mock.Arguments) {
+			close(repaired)
+		}).Return([]string{"mock1"}, nil)
+
+		value, err := quorumGetStrategy.Get(ctx, "query", units, targets, saveMock, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "worked", value)
+
+		select {
+		case <-repaired:
+		case <-time.After(time.Second):
+			t.Fatal("the missing unit was not repaired")
+		}
+	})
+
+	t.Run("should report the outcome of the repair", func(t *testing.T) {
+		quorumGetSetup()
+		ctx := context.Background()
+		repaired := make(chan error, 1)
+		quorumGetStrategy.OnRepair = func(query string, err error) {
+			assert.Equal(t, "query", query)
+			repaired <- err
+		}
+
+		mock1.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		mock2.On("Get", "query", mock.Anything).Return("worked", nil)
+		mock3.On("Get", "query", mock.Anything).Return("worked", nil)
+		saveMock.On("Save", mock.Anything, "query", "worked", units, []string{"mock1"}, mock.Anything).Return([]string{}, fmt.Errorf("down"))
+
+		value, err := quorumGetStrategy.Get(ctx, "query", units, targets, saveMock, 3)
+		assert.NoError(t, err)
+		assert.Equal(t, "worked", value)
+
+		select {
+		case err := <-repaired:
+			assert.EqualError(t, err, "down")
+		case <-time.After(time.Second):
+			t.Fatal("the repair was not reported")
+		}
+	})
+
+	t.Run("should use the configured resolver", func(t *testing.T) {
+		quorumGetSetup()
+		quorumGetStrategy.Resolver = func(values map[string]string) (string, error) {
+			return values["mock1"], nil
+		}
+		ctx := context.Background()
+		repaired := make(chan struct{})
+
+		mock1.On("Get", "query", mock.Anything).Return("newest", nil)
+		mock2.On("Get", "query", mock.Anything).Return("stale", nil)
+		mock3.On("Get", "query", mock.Anything).Return("stale", nil)
+		saveMock.On("Save", mock.Anything, "query", "newest", units, []string{"mock2", "mock3"}, mock.Anything).Run(func(mock.Arguments) {
+			close(repaired)
+		}).Return([]string{"mock2", "mock3"}, nil)
+
+		value, err := quorumGetStrategy.Get(ctx, "query", units, targets, saveMock, 3)
+
+		assert.NoError(t, err)
+		assert.Equal(t, "newest", value)
+
+		select {
+		case <-repaired:
+		case <-time.After(time.Second):
+			t.Fatal("the divergent units were not repaired")
+		}
+	})
+
+	t.Run("should report a miss when every unit of the quorum missed", func(t *testing.T) {
+		quorumGetSetup()
+		ctx := context.Background()
+
+		mock1.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		mock2.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		mock3.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+
+		_, err := quorumGetStrategy.Get(ctx, "query", units, targets, saveMock, 3)
+
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+		saveMock.AssertNotCalled(t, "Save")
+	})
+
+	t.Run("should return error when too many units fail to reach the quorum", func(t *testing.T) {
+		quorumGetSetup()
+		ctx := context.Background()
+
+		mock1.On("Get", "query", mock.Anything).Return("", fmt.Errorf("mock1 error"))
+		mock2.On("Get", "query", mock.Anything).Return("", fmt.Errorf("mock2 error"))
+		mock3.On("Get", "query", mock.Anything).Return("worked", nil)
+
+		_, err := quorumGetStrategy.Get(ctx, "query", units, targets, saveMock, 2)
+
+		assert.ErrorIs(t, err, protocols.ErrQuorumNotReached)
+		assert.NotErrorIs(t, err, protocols.ErrNotFound)
+	})
+}
+
+func TestStaleWhileRevalidateGet(t *testing.T) {
+
+	t.Run("should return the stale value and refresh it from the source in the background", func(t *testing.T) {
+		staleWhileRevalidateGetSetup()
+		ctx := context.Background()
+		staleCache.SaveWithMetadata(ctx, "query", "old", protocols.Metadata{WrittenAt: time.Now().Add(-time.Hour)})
+		staleSource.Save(ctx, "query", "new")
+
+		value, err := staleWhileRevalidateGetStrategy.Get(ctx, "query", units, []string{"cache", "source"}, &SequentialSaveStrategy[string, string]{})
+		assert.NoError(t, err)
+		assert.Equal(t, "old", value)
+
+		assert.NoError(t, <-staleRevalidated)
+		value, _ = staleCache.Get(ctx, "query")
+		assert.Equal(t, "new", value)
+	})
+
+	t.Run("should not refresh a fresh value", func(t *testing.T) {
+		staleWhileRevalidateGetSetup()
+		ctx := context.Background()
+		staleCache.Save(ctx, "query", "cached")
+		staleSource.Save(ctx, "query", "new")
+
+		value, err := staleWhileRevalidateGetStrategy.Get(ctx, "query", units, []string{"cache", "source"}, &SequentialSaveStrategy[string, string]{})
+		assert.NoError(t, err)
+		assert.Equal(t, "cached", value)
+
+		select {
+		case <-staleRevalidated:
+			t.Fatal("a fresh value was refreshed")
+		case <-time.After(20 * time.Millisecond):
+		}
+	})
+
+	t.Run("should delete the value from the faster units when the source no longer has it", func(t *testing.T) {
+		staleWhileRevalidateGetSetup()
+		ctx := context.Background()
+		staleCache.SaveWithMetadata(ctx, "query", "old", protocols.Metadata{WrittenAt: time.Now(), Stale: true})
+
+		value, err := staleWhileRevalidateGetStrategy.Get(ctx, "query", units, []string{"cache", "source"}, &SequentialSaveStrategy[string, string]{})
+		assert.NoError(t, err)
+		assert.Equal(t, "old", value)
+
+		assert.NoError(t, <-staleRevalidated)
+		_, err = staleCache.Get(ctx, "query")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+	})
+
+	t.Run("should refresh a key once however many gets find it stale", func(t *testing.T) {
+		staleWhileRevalidateGetSetup()
+		ctx := context.Background()
+		staleCache.SaveWithMetadata(ctx, "query", "old", protocols.Metadata{Stale: true})
+		release := make(chan struct{})
+		source := unit_test.NewUnitMock()
+		source.On("Get", "query", mock.Anything).Run(func(mock.Arguments) {
+			<-release
+		}).Return("new", nil)
+		units["source"] = source
+
+		var wg sync.WaitGroup
+		for c := 0; c < 50; c++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				value, err := staleWhileRevalidateGetStrategy.Get(ctx, "query", units, []string{"cache", "source"}, &SequentialSaveStrategy[string, string]{})
+				assert.NoError(t, err)
+				assert.Equal(t, "old", value)
+			}()
+		}
+		wg.Wait()
+		close(release)
+
+		assert.NoError(t, <-staleRevalidated)
+		source.AssertNumberOfCalls(t, "Get", 1)
+		value, _ := staleCache.Get(ctx, "query")
+		assert.Equal(t, "new", value)
+	})
+
+	t.Run("should treat the values of units without metadata as fresh", func(t *testing.T) {
+		staleWhileRevalidateGetSetup()
+		ctx := context.Background()
+		plain := unit_test.NewUnitMock()
+		plain.On("Get", "query", mock.Anything).Return("plain", nil)
+		units["plain"] = plain
+
+		value, err := staleWhileRevalidateGetStrategy.Get(ctx, "query", units, []string{"plain", "source"}, &SequentialSaveStrategy[string, string]{})
+		assert.NoError(t, err)
+		assert.Equal(t, "plain", value)
+
+		select {
+		case <-staleRevalidated:
+			t.Fatal("a value without metadata was refreshed")
+		case <-time.After(20 * time.Millisecond):
+		}
+	})
 }
@@ -0,0 +1,144 @@
+package strategies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// RollbackSaveStrategy saves with Strategy (Sequential when nil) and undoes the
+// save when it fails: every unit Strategy reports as saved gets back the value
+// it held before, read with Get ahead of the save, or loses the key if it held
+// none. The save does not start when a previous value cannot be read.
+//
+// After a successful rollback the error of the save is returned with no saved
+// units. Units whose rollback failed are returned as saved, along with a
+// *protocols.RollbackError. The save is atomic only as far as no one else
+// writes the key meanwhile: a write made between the read and the rollback is
+// overwritten.
+type RollbackSaveStrategy[K any, V any] struct {
+	Strategy         protocols.SaveStrategy[K, V]
+	BatchConcurrency int
+}
+
+type previousValue[V any] struct {
+	value V
+	found bool
+}
+
+func (r *RollbackSaveStrategy[K, V]) strategy() protocols.SaveStrategy[K, V] {
+	if r.Strategy == nil {
+		return &SequentialSaveStrategy[K, V]{BatchConcurrency: r.BatchConcurrency}
+	}
+	return r.Strategy
+}
+
+func (r *RollbackSaveStrategy[K, V]) Save(ctx context.Context, query K, item V, units map[string]protocols.StorageUnit[K, V], targets []string, auxiliary ...any) ([]string, error) {
+	previous := make(map[string]previousValue[V], len(targets))
+	failed := protocols.UnitErrors{}
+	for _, key := range targets {
+		value, err := units[key].Get(ctx, query)
+		switch {
+		case err == nil:
+			previous[key] = previousValue[V]{value: value, found: true}
+		case errors.Is(err, protocols.ErrNotFound):
+			previous[key] = previousValue[V]{}
+		default:
+			failed[key] = err
+		}
+	}
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("error reading previous value %w", failed)
+	}
+
+	saved, err := r.strategy().Save(ctx, query, item, units, targets, auxiliary...)
+	if err == nil {
+		return saved, nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range saved {
+		var rollbackErr error
+		if previous[key].found {
+			rollbackErr = units[key].Save(ctx, query, previous[key].value)
+		} else if rollbackErr = units[key].Delete(ctx, query); errors.Is(rollbackErr, protocols.ErrNotFound) {
+			rollbackErr = nil
+		}
+		if rollbackErr != nil {
+			failed[key] = rollbackErr
+		}
+	}
+
+	if len(failed) > 0 {
+		return failed.Units(), &protocols.RollbackError{Err: err, Failed: failed}
+	}
+	return nil, err
+}
+
+func (r *RollbackSaveStrategy[K, V]) SaveMany(ctx context.Context, queries []K, items []V, units map[string]protocols.StorageUnit[K, V], targets []string, auxiliary ...any) ([]string, error) {
+	strategy, ok := r.strategy().(protocols.BatchSaveStrategy[K, V])
+	if !ok {
+		return nil, fmt.Errorf("%w: rolled back save strategy", protocols.ErrBatchUnsupported)
+	}
+
+	previous := make(map[string][]previousValue[V], len(targets))
+	failed := protocols.UnitErrors{}
+	for _, key := range targets {
+		values, errs := getMany(ctx, AsBatchUnit(units[key], r.BatchConcurrency), queries)
+		previous[key] = make([]previousValue[V], len(queries))
+		for index, err := range errs {
+			switch {
+			case err == nil:
+				previous[key][index] = previousValue[V]{value: values[index], found: true}
+			case !errors.Is(err, protocols.ErrNotFound):
+				failed[key] = errors.Join(failed[key], err)
+			}
+		}
+	}
+	if len(failed) > 0 {
+		return nil, fmt.Errorf("error reading previous value %w", failed)
+	}
+
+	saved, err := strategy.SaveMany(ctx, queries, items, units, targets, auxiliary...)
+	if err == nil {
+		return saved, nil
+	}
+
+	ctx = context.WithoutCancel(ctx)
+	for _, key := range saved {
+		var restore, remove []int
+		for index, value := range previous[key] {
+			if value.found {
+				restore = append(restore, index)
+			} else {
+				remove = append(remove, index)
+			}
+		}
+
+		unit := AsBatchUnit(units[key], r.BatchConcurrency)
+		var errs []error
+		if len(restore) > 0 {
+			values := make([]V, len(restore))
+			for c, index := range restore {
+				values[c] = previous[key][index].value
+			}
+			errs = append(errs, unit.SaveMany(ctx, pick(queries, restore), values))
+		}
+		if len(remove) > 0 {
+			errs = append(errs, unit.DeleteMany(ctx, pick(queries, remove)))
+		}
+		if rollbackErr := errors.Join(errs...); rollbackErr != nil {
+			failed[key] = rollbackErr
+		}
+	}
+
+	if len(failed) > 0 {
+		return failed.Units(), &protocols.RollbackError{Err: err, Failed: failed}
+	}
+	return nil, err
+}
+
+var _ protocols.SaveStrategy[any, any] = (*RollbackSaveStrategy[any, any])(nil)
+var _ protocols.BatchSaveStrategy[any, any] = (*RollbackSaveStrategy[any, any])(nil)
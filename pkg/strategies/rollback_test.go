@@ -0,0 +1,103 @@
+package strategies
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var rollbackFirst *unit_test.MemoryUnit[string, string]
+var rollbackSecond *unit_test.MemoryUnit[string, string]
+
+func rollbackSaveSetup() {
+	rollbackFirst = unit_test.NewMemoryUnit[string, string]()
+	rollbackSecond = unit_test.NewMemoryUnit[string, string]()
+	failing := unit_test.NewUnitMock()
+	failing.On("Get", mock.Anything, mock.Anything).Return("", protocols.ErrNotFound)
+	failing.On("Save", mock.Anything, mock.Anything, mock.Anything).Return(errors.New("disk full"))
+	units = map[string]protocols.StorageUnit[string, string]{"first": rollbackFirst, "second": rollbackSecond, "failing": failing}
+}
+
+func TestRollbackSave(t *testing.T) {
+
+	t.Run("should restore the previous value or delete the key in the saved units", func(t *testing.T) {
+		rollbackSaveSetup()
+		ctx := context.Background()
+		rollbackFirst.Save(ctx, "query", "old")
+
+		saved, err := (&RollbackSaveStrategy[string, string]{}).Save(ctx, "query", "new", units, []string{"first", "second", "failing"})
+		assert.ErrorContains(t, err, "disk full")
+		assert.NotErrorIs(t, err, protocols.ErrRollbackFailed)
+		assert.Empty(t, saved)
+
+		value, _ := rollbackFirst.Get(ctx, "query")
+		assert.Equal(t, "old", value)
+		_, err = rollbackSecond.Get(ctx, "query")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+	})
+
+	t.Run("should return the saved units when nothing failed", func(t *testing.T) {
+		rollbackSaveSetup()
+		ctx := context.Background()
+
+		saved, err := (&RollbackSaveStrategy[string, string]{Strategy: &ParallelSaveStrategy[string, string]{}}).Save(ctx, "query", "new", units, []string{"first", "second"})
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"first", "second"}, saved)
+		value, _ := rollbackFirst.Get(ctx, "query")
+		assert.Equal(t, "new", value)
+		value, _ = rollbackSecond.Get(ctx, "query")
+		assert.Equal(t, "new", value)
+	})
+
+	t.Run("should not save when a previous value cannot be read", func(t *testing.T) {
+		rollbackSaveSetup()
+		broken := unit_test.NewUnitMock()
+		broken.On("Get", "query", mock.Anything).Return("", errors.New("connection refused"))
+		units["broken"] = broken
+		ctx := context.Background()
+
+		saved, err := (&RollbackSaveStrategy[string, string]{}).Save(ctx, "query", "new", units, []string{"first", "broken"})
+		assert.ErrorContains(t, err, "connection refused")
+		assert.Empty(t, saved)
+		_, err = rollbackFirst.Get(ctx, "query")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+		broken.AssertNotCalled(t, "Save", mock.Anything, mock.Anything, mock.Anything)
+	})
+
+	t.Run("should report the units whose rollback failed", func(t *testing.T) {
+		rollbackSaveSetup()
+		stuck := unit_test.NewUnitMock()
+		stuck.On("Get", "query", mock.Anything).Return("", protocols.ErrNotFound)
+		stuck.On("Save", "query", "new", mock.Anything).Return(nil)
+		stuck.On("Delete", "query", mock.Anything).Return(errors.New("read-only replica"))
+		units["stuck"] = stuck
+		ctx := context.Background()
+
+		saved, err := (&RollbackSaveStrategy[string, string]{}).Save(ctx, "query", "new", units, []string{"stuck", "first", "failing"})
+		assert.ErrorIs(t, err, protocols.ErrRollbackFailed)
+		var rollbackErr *protocols.RollbackError
+		assert.ErrorAs(t, err, &rollbackErr)
+		assert.ErrorContains(t, rollbackErr.Err, "disk full")
+		assert.Equal(t, []string{"stuck"}, rollbackErr.Failed.Units())
+		assert.Equal(t, []string{"stuck"}, saved)
+	})
+
+	t.Run("should roll back many keys", func(t *testing.T) {
+		rollbackSaveSetup()
+		ctx := context.Background()
+		rollbackFirst.Save(ctx, "kept", "old")
+
+		saved, err := (&RollbackSaveStrategy[string, string]{}).SaveMany(ctx, []string{"kept", "added"}, []string{"new", "new"}, units, []string{"first", "failing"})
+		assert.ErrorContains(t, err, "disk full")
+		assert.Empty(t, saved)
+		value, _ := rollbackFirst.Get(ctx, "kept")
+		assert.Equal(t, "old", value)
+		_, err = rollbackFirst.Get(ctx, "added")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+	})
+}
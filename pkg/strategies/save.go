@@ -8,7 +8,9 @@ import (
 	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
 )
 
-type SequentialSaveStrategy[K any, V any] struct{}
+type SequentialSaveStrategy[K any, V any] struct {
+	BatchConcurrency int
+}
 
 func (s *SequentialSaveStrategy[K, V]) Save(ctx context.Context, query K, item V, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
 	saved := make([]string, 0, len(units))
@@ -24,8 +26,7 @@ func (s *SequentialSaveStrategy[K, V]) Save(ctx context.Context, query K, item V
 		err := unit.Save(ctx, query, item)
 		if err != nil {
 			cancel()
-			err = fmt.Errorf("error saving unit %v: %v", key, err.Error())
-			return saved, err
+			return saved, fmt.Errorf("error saving unit %w", protocols.UnitErrors{key: err})
 		}
 		saved = append(saved, key)
 	}
@@ -34,7 +35,9 @@ func (s *SequentialSaveStrategy[K, V]) Save(ctx context.Context, query K, item V
 
 var _ protocols.SaveStrategy[any, any] = (*SequentialSaveStrategy[any, any])(nil)
 
-type ParallelSaveStrategy[K any, V any] struct{}
+type ParallelSaveStrategy[K any, V any] struct {
+	BatchConcurrency int
+}
 
 func (p *ParallelSaveStrategy[K, V]) Save(ctx context.Context, query K, item V, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
 	ctx, cancel := context.WithCancel(ctx)
@@ -43,7 +46,7 @@ func (p *ParallelSaveStrategy[K, V]) Save(ctx context.Context, query K, item V,
 	var wg sync.WaitGroup
 	mu := sync.Mutex{}
 	saved := make([]string, 0, len(units))
-	errCh := make(chan error, len(units))
+	errs := protocols.UnitErrors{}
 
 	for _, key := range targets {
 		wg.Add(1)
@@ -52,14 +55,17 @@ func (p *ParallelSaveStrategy[K, V]) Save(ctx context.Context, query K, item V,
 			defer wg.Done()
 
 			if ctx.Err() != nil {
-				errCh <- fmt.Errorf("context finalized: %w", ctx.Err())
+				mu.Lock()
+				errs[key] = fmt.Errorf("context finalized: %w", ctx.Err())
+				mu.Unlock()
 				return
 			}
 
 			if err := unit.Save(ctx, query, item); err != nil {
 				cancel()
-				err = fmt.Errorf("error saving unit %v: %v", key, err.Error())
-				errCh <- err
+				mu.Lock()
+				errs[key] = err
+				mu.Unlock()
 				return
 			}
 
@@ -70,15 +76,79 @@ func (p *ParallelSaveStrategy[K, V]) Save(ctx context.Context, query K, item V,
 	}
 
 	wg.Wait()
-	close(errCh)
 
-	for err := range errCh {
-		if err != nil {
-			return saved, err
-		}
+	if len(errs) > 0 {
+		return saved, fmt.Errorf("error saving unit %w", errs)
 	}
 
 	return saved, nil
 }
 
 var _ protocols.SaveStrategy[any, any] = (*ParallelSaveStrategy[any, any])(nil)
+
+type QuorumSaveStrategy[K any, V any] struct{}
+
+type quorumSaveResult struct {
+	key string
+	err error
+}
+
+// Save writes to every target concurrently and returns as soon as the write
+// quorum acknowledged, passed as an int in auxiliary (a majority of the targets
+// when absent or zero), or as soon as it can no longer be reached. Writes still
+// running at that point are canceled and their outcome is not reported; once
+// the last of them returned, Save calls the function carried by
+// protocols.ContextWithSettled.
+func (q *QuorumSaveStrategy[K, V]) Save(ctx context.Context, query K, item V, units map[string]protocols.StorageUnit[K, V], targets []string, auxiliary ...any) ([]string, error) {
+	quorum := len(targets)/2 + 1
+	if len(auxiliary) > 0 {
+		if n, ok := auxiliary[0].(int); ok && n > 0 {
+			quorum = n
+		}
+	}
+
+	if quorum > len(targets) {
+		return nil, fmt.Errorf("%w: write quorum %v is greater than the %v targets", protocols.ErrQuorumNotReached, quorum, len(targets))
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var writing sync.WaitGroup
+	writing.Add(len(targets))
+	resultCh := make(chan quorumSaveResult, len(targets))
+	for _, key := range targets {
+		go func(key string, unit protocols.StorageUnit[K, V]) {
+			defer writing.Done()
+			resultCh <- quorumSaveResult{key: key, err: unit.Save(ctx, query, item)}
+		}(key, units[key])
+	}
+	if settled := protocols.SettledFromContext(ctx); settled != nil {
+		go func() {
+			writing.Wait()
+			settled()
+		}()
+	}
+
+	saved := make([]string, 0, len(targets))
+	errs := protocols.UnitErrors{}
+	for range targets {
+		result := <-resultCh
+		if result.err != nil {
+			errs[result.key] = result.err
+		} else {
+			saved = append(saved, result.key)
+		}
+
+		if len(saved) >= quorum {
+			return saved, nil
+		}
+		if len(errs) > len(targets)-quorum {
+			return saved, fmt.Errorf("%w: %v of %v acknowledged, error saving unit %w", protocols.ErrQuorumNotReached, len(saved), quorum, errs)
+		}
+	}
+
+	return saved, nil
+}
+
+var _ protocols.SaveStrategy[any, any] = (*QuorumSaveStrategy[any, any])(nil)
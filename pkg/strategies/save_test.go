@@ -4,13 +4,18 @@ import (
 	"context"
 	"fmt"
 	"testing"
+	"time"
 
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 )
 
 var sequentialSaveStrategy SequentialSaveStrategy[string, string]
 var parallelSaveStrategy ParallelSaveStrategy[string, string]
+var quorumSaveStrategy QuorumSaveStrategy[string, string]
+var mock3 *unit_test.UnitMock
 
 func sequentialSaveSetup() {
 	sequentialSaveStrategy = SequentialSaveStrategy[string, string]{}
@@ -22,6 +27,14 @@ func parallelSaveSetup() {
 	initialSetup()
 }
 
+func quorumSaveSetup() {
+	quorumSaveStrategy = QuorumSaveStrategy[string, string]{}
+	initialSetup()
+	mock3 = unit_test.NewUnitMock()
+	units["mock3"] = mock3
+	targets = append(targets, "mock3")
+}
+
 func TestSequentialSave(t *testing.T) {
 
 	t.Run("should return all units and without error", func(t *testing.T) {
@@ -93,13 +106,20 @@ func TestParallelSave(t *testing.T) {
 		parallelSaveSetup()
 		ctx := context.Background()
 
-		mock1.On("Save", "query", "saved", mock.Anything).Return(fmt.Errorf("unit1 error"))
-		mock2.On("Save", "query", "saved", mock.Anything).Return(nil)
+		mock2Saved := make(chan time.Time)
+		mock1.On("Save", "query", "saved", mock.Anything).WaitUntil(mock2Saved).Return(fmt.Errorf("unit1 error"))
+		mock2.On("Save", "query", "saved", mock.Anything).Run(func(mock.Arguments) {
+			close(mock2Saved)
+		}).Return(nil)
 
 		saved, err := parallelSaveStrategy.Save(ctx, "query", "saved", units, targets)
 
 		assert.ErrorContains(t, err, "error saving unit mock1: unit1 error")
 
+		var unitErrs protocols.UnitErrors
+		assert.ErrorAs(t, err, &unitErrs)
+		assert.EqualError(t, unitErrs["mock1"], "unit1 error")
+
 		assert.ElementsMatch(t, saved, []string{"mock2"})
 
 		mock1.AssertExpectations(t)
@@ -121,3 +141,106 @@ func TestParallelSave(t *testing.T) {
 	})
 
 }
+
+func TestQuorumSave(t *testing.T) {
+
+	t.Run("should return once the majority acknowledged when no quorum is passed", func(t *testing.T) {
+		quorumSaveSetup()
+		ctx := context.Background()
+		release := make(chan time.Time)
+		defer close(release)
+
+		mock1.On("Save", "query", "worked", mock.Anything).Return(nil)
+		mock2.On("Save", "query", "worked", mock.Anything).Return(nil)
+		mock3.On("Save", "query", "worked", mock.Anything).WaitUntil(release).Return(nil)
+
+		saved, err := quorumSaveStrategy.Save(ctx, "query", "worked", units, targets)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"mock1", "mock2"}, saved)
+	})
+
+	t.Run("should cancel the writes still running and report when they settled", func(t *testing.T) {
+		quorumSaveSetup()
+		returned := make(chan struct{})
+		settled := make(chan struct{})
+		ctx := protocols.ContextWithSettled(context.Background(), func() {
+			close(settled)
+		})
+
+		mock1.On("Save", "query", "worked", mock.Anything).Return(nil)
+		mock2.On("Save", "query", "worked", mock.Anything).Return(nil)
+		mock3.On("Save", "query", "worked", mock.Anything).Run(func(args mock.Arguments) {
+			<-args.Get(2).(context.Context).Done()
+			<-returned
+		}).Return(context.Canceled)
+
+		saved, err := quorumSaveStrategy.Save(ctx, "query", "worked", units, targets)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"mock1", "mock2"}, saved)
+
+		select {
+		case <-settled:
+			t.Fatal("settled before the last write returned")
+		case <-time.After(10 * time.Millisecond):
+		}
+		close(returned)
+		<-settled
+	})
+
+	t.Run("should wait for the configured quorum", func(t *testing.T) {
+		quorumSaveSetup()
+		ctx := context.Background()
+
+		mock1.On("Save", "query", "worked", mock.Anything).Return(nil)
+		mock2.On("Save", "query", "worked", mock.Anything).Return(nil)
+		mock3.On("Save", "query", "worked", mock.Anything).After(10 * time.Millisecond).Return(nil)
+
+		saved, err := quorumSaveStrategy.Save(ctx, "query", "worked", units, targets, 3)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"mock1", "mock2", "mock3"}, saved)
+
+		mock1.AssertExpectations(t)
+		mock2.AssertExpectations(t)
+		mock3.AssertExpectations(t)
+	})
+
+	t.Run("should succeed when the failures still allow the quorum", func(t *testing.T) {
+		quorumSaveSetup()
+		ctx := context.Background()
+
+		mock1.On("Save", "query", "worked", mock.Anything).Return(fmt.Errorf("unit1 error"))
+		mock2.On("Save", "query", "worked", mock.Anything).Return(nil)
+		mock3.On("Save", "query", "worked", mock.Anything).Return(nil)
+
+		saved, err := quorumSaveStrategy.Save(ctx, "query", "worked", units, targets, 2)
+		assert.NoError(t, err)
+		assert.ElementsMatch(t, []string{"mock2", "mock3"}, saved)
+	})
+
+	t.Run("should return error as soon as the quorum can no longer be reached", func(t *testing.T) {
+		quorumSaveSetup()
+		ctx := context.Background()
+
+		mock1.On("Save", "query", "worked", mock.Anything).Return(fmt.Errorf("unit1 error"))
+		mock2.On("Save", "query", "worked", mock.Anything).Return(fmt.Errorf("unit2 error"))
+		mock3.On("Save", "query", "worked", mock.Anything).Return(nil)
+
+		_, err := quorumSaveStrategy.Save(ctx, "query", "worked", units, targets, 2)
+		assert.ErrorIs(t, err, protocols.ErrQuorumNotReached)
+
+		var unitErrs protocols.UnitErrors
+		assert.ErrorAs(t, err, &unitErrs)
+		assert.Equal(t, []string{"mock1", "mock2"}, unitErrs.Units())
+	})
+
+	t.Run("should return error when the quorum is greater than the targets", func(t *testing.T) {
+		quorumSaveSetup()
+		ctx := context.Background()
+
+		saved, err := quorumSaveStrategy.Save(ctx, "query", "worked", units, targets, 4)
+		assert.ErrorIs(t, err, protocols.ErrQuorumNotReached)
+		assert.Empty(t, saved)
+
+		mock1.AssertNotCalled(t, "Save")
+	})
+}
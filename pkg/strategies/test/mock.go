@@ -33,9 +33,9 @@ type MockDeleteStrategy struct {
 	mock.Mock
 }
 
-func (m *MockDeleteStrategy) Delete(ctx context.Context, query string, units map[string]protocols.StorageUnit[string, string], targets []string, _ ...any) error {
+func (m *MockDeleteStrategy) Delete(ctx context.Context, query string, units map[string]protocols.StorageUnit[string, string], targets []string, _ ...any) ([]string, error) {
 	args := m.Called(ctx, query, units, targets)
-	return args.Error(0)
+	return args.Get(0).([]string), args.Error(1)
 }
 
 var _ protocols.DeleteStrategy[string, string] = (*MockDeleteStrategy)(nil)
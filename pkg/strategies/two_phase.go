@@ -0,0 +1,136 @@
+package strategies
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// TwoPhaseSaveStrategy saves through a two-phase commit: it prepares the write
+// in every target concurrently and commits it only when every target
+// prepared, aborting it in every target otherwise. Every target must implement
+// protocols.TransactionalStorageUnit; when one does not, the save fails with
+// protocols.ErrTransactionsUnsupported before any unit is called.
+//
+// Once every target prepared, the commit runs to the end even if the context
+// is canceled. The saved units are the ones that committed; a commit that
+// still fails is returned along with them, since the other targets can no
+// longer be aborted.
+type TwoPhaseSaveStrategy[K any, V any] struct{}
+
+type transactionFunc[K any, V any] func(ctx context.Context, unit protocols.TransactionalStorageUnit[K, V]) error
+
+func (t *TwoPhaseSaveStrategy[K, V]) Save(ctx context.Context, query K, item V, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	return t.run(ctx, units, targets, func(ctx context.Context, tx string, unit protocols.TransactionalStorageUnit[K, V]) error {
+		return unit.Prepare(ctx, tx, query, item)
+	})
+}
+
+// SaveMany prepares every key in a single transaction per target.
+func (t *TwoPhaseSaveStrategy[K, V]) SaveMany(ctx context.Context, queries []K, items []V, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	return t.run(ctx, units, targets, func(ctx context.Context, tx string, unit protocols.TransactionalStorageUnit[K, V]) error {
+		for c := range queries {
+			if err := unit.Prepare(ctx, tx, queries[c], items[c]); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (t *TwoPhaseSaveStrategy[K, V]) run(ctx context.Context, units map[string]protocols.StorageUnit[K, V], targets []string, prepare func(ctx context.Context, tx string, unit protocols.TransactionalStorageUnit[K, V]) error) ([]string, error) {
+	if len(targets) == 0 {
+		return nil, protocols.ErrNoTargets
+	}
+
+	var unsupported []string
+	for _, key := range targets {
+		if _, ok := units[key].(protocols.TransactionalStorageUnit[K, V]); !ok || !supportsTransactions(units[key]) {
+			unsupported = append(unsupported, key)
+		}
+	}
+	if len(unsupported) > 0 {
+		return nil, fmt.Errorf("%w: %v", protocols.ErrTransactionsUnsupported, strings.Join(unsupported, ", "))
+	}
+
+	tx, err := newTransaction()
+	if err != nil {
+		return nil, fmt.Errorf("error starting transaction: %w", err)
+	}
+
+	failed := eachTransactional(ctx, units, targets, func(ctx context.Context, unit protocols.TransactionalStorageUnit[K, V]) error {
+		return prepare(ctx, tx, unit)
+	})
+	if len(failed) > 0 {
+		err := fmt.Errorf("error preparing unit %w", failed)
+		aborted := eachTransactional(context.WithoutCancel(ctx), units, targets, func(ctx context.Context, unit protocols.TransactionalStorageUnit[K, V]) error {
+			return unit.Abort(ctx, tx)
+		})
+		if len(aborted) > 0 {
+			err = errors.Join(err, fmt.Errorf("error aborting unit %w", aborted))
+		}
+		return nil, err
+	}
+
+	failed = eachTransactional(context.WithoutCancel(ctx), units, targets, func(ctx context.Context, unit protocols.TransactionalStorageUnit[K, V]) error {
+		return unit.Commit(ctx, tx)
+	})
+	committed := others(targets, failed.Units())
+	if len(failed) > 0 {
+		return committed, fmt.Errorf("error committing unit %w", failed)
+	}
+	return committed, nil
+}
+
+// eachTransactional calls fn on every target concurrently and returns the
+// errors.
+func eachTransactional[K any, V any](ctx context.Context, units map[string]protocols.StorageUnit[K, V], targets []string, fn transactionFunc[K, V]) protocols.UnitErrors {
+	var wg sync.WaitGroup
+	mu := sync.Mutex{}
+	errs := protocols.UnitErrors{}
+
+	for _, key := range targets {
+		wg.Add(1)
+		go func(key string, unit protocols.TransactionalStorageUnit[K, V]) {
+			defer wg.Done()
+			if err := fn(ctx, unit); err != nil {
+				mu.Lock()
+				errs[key] = err
+				mu.Unlock()
+			}
+		}(key, units[key].(protocols.TransactionalStorageUnit[K, V]))
+	}
+
+	wg.Wait()
+	return errs
+}
+
+// supportsTransactions reports whether the unit behind the decorators
+// implements protocols.TransactionalStorageUnit.
+func supportsTransactions[K any, V any](unit protocols.StorageUnit[K, V]) bool {
+	for {
+		wrapper, ok := unit.(protocols.UnitWrapper[K, V])
+		if !ok {
+			_, ok := unit.(protocols.TransactionalStorageUnit[K, V])
+			return ok
+		}
+		unit = wrapper.Unwrap()
+	}
+}
+
+func newTransaction() (string, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(id), nil
+}
+
+var _ protocols.SaveStrategy[any, any] = (*TwoPhaseSaveStrategy[any, any])(nil)
+var _ protocols.BatchSaveStrategy[any, any] = (*TwoPhaseSaveStrategy[any, any])(nil)
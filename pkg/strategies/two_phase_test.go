@@ -0,0 +1,107 @@
+package strategies
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
+	"github.com/stretchr/testify/assert"
+)
+
+type failingTransactionalUnit struct {
+	*unit_test.TransactionalMemoryUnit[string, string]
+	prepareErr error
+	commitErr  error
+}
+
+func (f failingTransactionalUnit) Prepare(ctx context.Context, tx string, query string, item string) error {
+	if f.prepareErr != nil {
+		return f.prepareErr
+	}
+	return f.TransactionalMemoryUnit.Prepare(ctx, tx, query, item)
+}
+
+func (f failingTransactionalUnit) Commit(ctx context.Context, tx string) error {
+	if f.commitErr != nil {
+		return f.commitErr
+	}
+	return f.TransactionalMemoryUnit.Commit(ctx, tx)
+}
+
+var twoPhaseFirst *unit_test.TransactionalMemoryUnit[string, string]
+var twoPhaseSecond *unit_test.TransactionalMemoryUnit[string, string]
+
+func twoPhaseSaveSetup() {
+	twoPhaseFirst = unit_test.NewTransactionalMemoryUnit[string, string]()
+	twoPhaseSecond = unit_test.NewTransactionalMemoryUnit[string, string]()
+	units = map[string]protocols.StorageUnit[string, string]{"first": twoPhaseFirst, "second": twoPhaseSecond}
+}
+
+func TestTwoPhaseSave(t *testing.T) {
+
+	t.Run("should commit in every target once all of them prepared", func(t *testing.T) {
+		twoPhaseSaveSetup()
+		ctx := context.Background()
+
+		saved, err := (&TwoPhaseSaveStrategy[string, string]{}).Save(ctx, "query", "value", units, []string{"first", "second"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, saved)
+		for _, unit := range []*unit_test.TransactionalMemoryUnit[string, string]{twoPhaseFirst, twoPhaseSecond} {
+			value, _ := unit.Get(ctx, "query")
+			assert.Equal(t, "value", value)
+			assert.Equal(t, 0, unit.Pending())
+		}
+	})
+
+	t.Run("should abort in every target when one failed to prepare", func(t *testing.T) {
+		twoPhaseSaveSetup()
+		units["second"] = failingTransactionalUnit{TransactionalMemoryUnit: twoPhaseSecond, prepareErr: errors.New("lock timeout")}
+		ctx := context.Background()
+
+		saved, err := (&TwoPhaseSaveStrategy[string, string]{}).Save(ctx, "query", "value", units, []string{"first", "second"})
+		assert.ErrorContains(t, err, "lock timeout")
+		assert.Empty(t, saved)
+		_, err = twoPhaseFirst.Get(ctx, "query")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+		assert.Equal(t, 0, twoPhaseFirst.Pending())
+	})
+
+	t.Run("should reject a non-transactional unit before calling any unit", func(t *testing.T) {
+		twoPhaseSaveSetup()
+		units["plain"] = unit_test.NewMemoryUnit[string, string]()
+		ctx := context.Background()
+
+		saved, err := (&TwoPhaseSaveStrategy[string, string]{}).Save(ctx, "query", "value", units, []string{"first", "plain"})
+		assert.ErrorIs(t, err, protocols.ErrTransactionsUnsupported)
+		assert.ErrorContains(t, err, "plain")
+		assert.Empty(t, saved)
+		assert.Equal(t, 0, twoPhaseFirst.Pending())
+	})
+
+	t.Run("should return the committed units along with a failed commit", func(t *testing.T) {
+		twoPhaseSaveSetup()
+		units["second"] = failingTransactionalUnit{TransactionalMemoryUnit: twoPhaseSecond, commitErr: errors.New("connection lost")}
+		ctx := context.Background()
+
+		saved, err := (&TwoPhaseSaveStrategy[string, string]{}).Save(ctx, "query", "value", units, []string{"first", "second"})
+		var unitErrors protocols.UnitErrors
+		assert.ErrorAs(t, err, &unitErrors)
+		assert.Equal(t, []string{"second"}, unitErrors.Units())
+		assert.Equal(t, []string{"first"}, saved)
+	})
+
+	t.Run("should save many keys in a single transaction", func(t *testing.T) {
+		twoPhaseSaveSetup()
+		ctx := context.Background()
+
+		saved, err := (&TwoPhaseSaveStrategy[string, string]{}).SaveMany(ctx, []string{"a", "b"}, []string{"1", "2"}, units, []string{"first", "second"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"first", "second"}, saved)
+		value, _ := twoPhaseSecond.Get(ctx, "b")
+		assert.Equal(t, "2", value)
+		value, _ = twoPhaseFirst.Get(ctx, "a")
+		assert.Equal(t, "1", value)
+	})
+}
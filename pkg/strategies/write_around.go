@@ -0,0 +1,126 @@
+package strategies
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// WriteAroundSaveStrategy saves only to the sources of truth among the targets,
+// the units added with protocols.PrimaryRole or else the last target, and then
+// deletes the key from the other targets so no stale copy survives the write.
+// They are populated again by the backfill of the Cache get strategy. Targets
+// explicitly marked durable, with protocols.ReplicaRole or protocols.Durable,
+// keep their copy. With KeepCaches every other target is left untouched.
+//
+// The saved units are the sources only. A failed invalidation is reported
+// after the sources were saved, so the saved units are returned along with it.
+type WriteAroundSaveStrategy[K any, V any] struct {
+	KeepCaches       bool
+	BatchConcurrency int
+}
+
+func (w *WriteAroundSaveStrategy[K, V]) Save(ctx context.Context, query K, item V, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	if len(targets) == 0 {
+		return nil, protocols.ErrNoTargets
+	}
+
+	sources := protocols.SourceTargets(ctx, targets)
+	saved := make([]string, 0, len(sources))
+	for _, key := range sources {
+		if ctx.Err() != nil {
+			return saved, ctx.Err()
+		}
+		if err := units[key].Save(ctx, query, item); err != nil {
+			return saved, fmt.Errorf("error saving unit %w", protocols.UnitErrors{key: err})
+		}
+		saved = append(saved, key)
+	}
+
+	if w.KeepCaches {
+		return saved, nil
+	}
+
+	failed := protocols.UnitErrors{}
+	for _, key := range protocols.DisposableTargets(ctx, others(targets, sources)) {
+		if err := units[key].Delete(ctx, query); err != nil && !errors.Is(err, protocols.ErrNotFound) {
+			failed[key] = err
+		}
+	}
+	if len(failed) > 0 {
+		return saved, fmt.Errorf("error invalidating unit %w", failed)
+	}
+	return saved, nil
+}
+
+func (w *WriteAroundSaveStrategy[K, V]) SaveMany(ctx context.Context, queries []K, items []V, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	if len(targets) == 0 {
+		return nil, protocols.ErrNoTargets
+	}
+
+	sources := protocols.SourceTargets(ctx, targets)
+	saved := make([]string, 0, len(sources))
+	for _, key := range sources {
+		if ctx.Err() != nil {
+			return saved, ctx.Err()
+		}
+		if err := AsBatchUnit(units[key], w.BatchConcurrency).SaveMany(ctx, queries, items); err != nil {
+			return saved, fmt.Errorf("error saving unit %w", protocols.UnitErrors{key: err})
+		}
+		saved = append(saved, key)
+	}
+
+	if w.KeepCaches {
+		return saved, nil
+	}
+
+	failed := protocols.UnitErrors{}
+	for _, key := range protocols.DisposableTargets(ctx, others(targets, sources)) {
+		if err := ignoreNotFound(AsBatchUnit(units[key], w.BatchConcurrency).DeleteMany(ctx, queries)); err != nil {
+			failed[key] = err
+		}
+	}
+	if len(failed) > 0 {
+		return saved, fmt.Errorf("error invalidating unit %w", failed)
+	}
+	return saved, nil
+}
+
+// ignoreNotFound drops the ErrNotFound of the keys a batch delete had nothing
+// to remove for.
+func ignoreNotFound(err error) error {
+	var batchErrs protocols.BatchErrors
+	if !errors.As(err, &batchErrs) {
+		if errors.Is(err, protocols.ErrNotFound) {
+			return nil
+		}
+		return err
+	}
+	rest := make(protocols.BatchErrors, len(batchErrs))
+	for c, keyErr := range batchErrs {
+		if !errors.Is(keyErr, protocols.ErrNotFound) {
+			rest[c] = keyErr
+		}
+	}
+	return rest.ErrorOrNil()
+}
+
+// others returns the targets that are not in excluded, in order.
+func others(targets []string, excluded []string) []string {
+	skip := make(map[string]bool, len(excluded))
+	for _, key := range excluded {
+		skip[key] = true
+	}
+	var rest []string
+	for _, key := range targets {
+		if !skip[key] {
+			rest = append(rest, key)
+		}
+	}
+	return rest
+}
+
+var _ protocols.SaveStrategy[any, any] = (*WriteAroundSaveStrategy[any, any])(nil)
+var _ protocols.BatchSaveStrategy[any, any] = (*WriteAroundSaveStrategy[any, any])(nil)
@@ -0,0 +1,150 @@
+package strategies
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var writeAroundContext context.Context
+
+func writeAroundSaveSetup() {
+	units = map[string]protocols.StorageUnit[string, string]{
+		"cache":    unit_test.NewMemoryUnit[string, string](),
+		"replica":  unit_test.NewMemoryUnit[string, string](),
+		"database": unit_test.NewMemoryUnit[string, string](),
+	}
+	for _, key := range []string{"cache", "replica", "database"} {
+		units[key].Save(context.Background(), "query", "old")
+	}
+	writeAroundContext = protocols.ContextWithUnitOptions(context.Background(), map[string]protocols.UnitOptions{
+		"cache":   {Role: protocols.CacheRole},
+		"replica": {Role: protocols.ReplicaRole},
+	})
+}
+
+func TestWriteAroundSave(t *testing.T) {
+
+	t.Run("should save to the last target and invalidate the others", func(t *testing.T) {
+		writeAroundSaveSetup()
+		ctx := context.Background()
+
+		saved, err := (&WriteAroundSaveStrategy[string, string]{}).Save(ctx, "query", "new", units, []string{"cache", "database"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"database"}, saved)
+
+		value, _ := units["database"].Get(ctx, "query")
+		assert.Equal(t, "new", value)
+		_, err = units["cache"].Get(ctx, "query")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+	})
+
+	t.Run("should not delete from a unit marked durable", func(t *testing.T) {
+		writeAroundSaveSetup()
+		ctx := protocols.ContextWithUnitOptions(context.Background(), map[string]protocols.UnitOptions{
+			"cache": {Durability: protocols.Durable},
+		})
+
+		_, err := (&WriteAroundSaveStrategy[string, string]{}).Save(ctx, "query", "new", units, []string{"cache", "database"})
+		assert.NoError(t, err)
+		value, _ := units["cache"].Get(ctx, "query")
+		assert.Equal(t, "old", value)
+	})
+
+	t.Run("should not delete from a durable replica", func(t *testing.T) {
+		writeAroundSaveSetup()
+		ctx := writeAroundContext
+
+		saved, err := (&WriteAroundSaveStrategy[string, string]{}).Save(ctx, "query", "new", units, []string{"cache", "replica", "database"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"database"}, saved)
+		_, err = units["cache"].Get(ctx, "query")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+		value, _ := units["replica"].Get(ctx, "query")
+		assert.Equal(t, "old", value)
+	})
+
+	t.Run("should save to the primary units wherever they are in the targets", func(t *testing.T) {
+		writeAroundSaveSetup()
+		ctx := writeAroundContext
+		ctx = protocols.ContextWithUnitOptions(ctx, map[string]protocols.UnitOptions{
+			"cache":    {Role: protocols.CacheRole},
+			"database": {Role: protocols.PrimaryRole},
+		})
+
+		saved, err := (&WriteAroundSaveStrategy[string, string]{}).Save(ctx, "query", "new", units, []string{"database", "cache"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"database"}, saved)
+		_, err = units["cache"].Get(ctx, "query")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+	})
+
+	t.Run("should leave the other targets untouched when keeping caches", func(t *testing.T) {
+		writeAroundSaveSetup()
+		ctx := writeAroundContext
+
+		saved, err := (&WriteAroundSaveStrategy[string, string]{KeepCaches: true}).Save(ctx, "query", "new", units, []string{"cache", "database"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"database"}, saved)
+		value, _ := units["cache"].Get(ctx, "query")
+		assert.Equal(t, "old", value)
+	})
+
+	t.Run("should not invalidate when the source failed", func(t *testing.T) {
+		writeAroundSaveSetup()
+		ctx := writeAroundContext
+		database := unit_test.NewUnitMock()
+		database.On("Save", "query", "new", mock.Anything).Return(errors.New("database down"))
+		units["database"] = database
+
+		saved, err := (&WriteAroundSaveStrategy[string, string]{}).Save(ctx, "query", "new", units, []string{"cache", "database"})
+		assert.ErrorContains(t, err, "database down")
+		assert.Empty(t, saved)
+		value, _ := units["cache"].Get(ctx, "query")
+		assert.Equal(t, "old", value)
+	})
+
+	t.Run("should report a failed invalidation with the saved sources", func(t *testing.T) {
+		writeAroundSaveSetup()
+		ctx := writeAroundContext
+		cache := unit_test.NewUnitMock()
+		cache.On("Delete", "query", mock.Anything).Return(errors.New("cache down"))
+		units["cache"] = cache
+
+		saved, err := (&WriteAroundSaveStrategy[string, string]{}).Save(ctx, "query", "new", units, []string{"cache", "database"})
+		var unitErrors protocols.UnitErrors
+		assert.ErrorAs(t, err, &unitErrors)
+		assert.Contains(t, unitErrors, "cache")
+		assert.Equal(t, []string{"database"}, saved)
+	})
+
+	t.Run("should save many to the source and invalidate the others", func(t *testing.T) {
+		writeAroundSaveSetup()
+		ctx := writeAroundContext
+
+		saved, err := (&WriteAroundSaveStrategy[string, string]{}).SaveMany(ctx, []string{"query", "other"}, []string{"new", "other"}, units, []string{"cache", "database"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"database"}, saved)
+		value, _ := units["database"].Get(ctx, "other")
+		assert.Equal(t, "other", value)
+		_, err = units["cache"].Get(ctx, "query")
+		assert.ErrorIs(t, err, protocols.ErrNotFound)
+	})
+
+	t.Run("should not report the keys a cache did not hold when saving many", func(t *testing.T) {
+		writeAroundSaveSetup()
+		ctx := writeAroundContext
+		cache := unit_test.NewBatchUnitMock()
+		cache.On("DeleteMany", []string{"query", "other"}, mock.Anything).Return(protocols.BatchErrors{nil, protocols.ErrNotFound})
+		units["cache"] = cache
+
+		saved, err := (&WriteAroundSaveStrategy[string, string]{}).SaveMany(ctx, []string{"query", "other"}, []string{"new", "other"}, units, []string{"cache", "database"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"database"}, saved)
+	})
+}
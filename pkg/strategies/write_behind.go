@@ -0,0 +1,301 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/decorators"
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+// WriteBehindConfig configures a WriteBehindSaveStrategy. The pending writes
+// are flushed BatchSize at a time (100 when zero), as soon as they are queued
+// and again every FlushInterval (a second when zero) while some are left.
+// Writes to a target are retried according to Retry (three attempts, 100ms
+// apart at first, when MaxAttempts is zero); OnError is called for every write
+// given up on.
+type WriteBehindConfig[K any, V any] struct {
+	BatchSize     int
+	FlushInterval time.Duration
+	Retry         protocols.RetryPolicy
+	OnError       func(write protocols.PendingWrite[K, V], target string, err error)
+}
+
+// WriteBehindSaveStrategy saves to the first target and returns once it
+// accepted the write. The writes to the other targets are queued and flushed
+// in the background, against the units returned by Units at that time, so
+// writes replayed from a durable queue after a restart reach the units of the
+// new process. Writes reach each target in the order they were queued.
+type WriteBehindSaveStrategy[K any, V any] struct {
+	queue  protocols.WriteQueue[K, V]
+	units  func() (map[string]protocols.StorageUnit[K, V], error)
+	config WriteBehindConfig[K, V]
+
+	flushMu sync.Mutex
+	written func(queries ...K)
+	notify  chan struct{}
+	stop    chan struct{}
+	done    chan struct{}
+	cancel  context.CancelFunc
+	closed  sync.Once
+}
+
+// NewWriteBehindSaveStrategy starts flushing queue in the background, which
+// goes on until Close. units is usually the function returned by the
+// DecoratedUnits method of the orchestrator, so the flushed writes go through
+// the same timeouts, retries and interceptors as the other unit calls.
+func NewWriteBehindSaveStrategy[K any, V any](queue protocols.WriteQueue[K, V], units func() (map[string]protocols.StorageUnit[K, V], error), config WriteBehindConfig[K, V]) *WriteBehindSaveStrategy[K, V] {
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = time.Second
+	}
+	if config.Retry.MaxAttempts == 0 {
+		config.Retry.MaxAttempts = 3
+		if config.Retry.InitialBackoff == 0 {
+			config.Retry.InitialBackoff = 100 * time.Millisecond
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	w := &WriteBehindSaveStrategy[K, V]{
+		queue:  queue,
+		units:  units,
+		config: config,
+		notify: make(chan struct{}, 1),
+		stop:   make(chan struct{}),
+		done:   make(chan struct{}),
+		cancel: cancel,
+	}
+	go w.run(ctx)
+	return w
+}
+
+func (w *WriteBehindSaveStrategy[K, V]) Save(ctx context.Context, query K, item V, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	if len(targets) == 0 {
+		return nil, protocols.ErrNoTargets
+	}
+
+	first := targets[0]
+	if err := units[first].Save(ctx, query, item); err != nil {
+		return nil, fmt.Errorf("error saving unit %w", protocols.UnitErrors{first: err})
+	}
+
+	if len(targets) > 1 {
+		write := protocols.PendingWrite[K, V]{Query: query, Item: item, Targets: append([]string(nil), targets[1:]...)}
+		if err := w.enqueue(ctx, write); err != nil {
+			return []string{first}, err
+		}
+	}
+	return []string{first}, nil
+}
+
+func (w *WriteBehindSaveStrategy[K, V]) SaveMany(ctx context.Context, queries []K, items []V, units map[string]protocols.StorageUnit[K, V], targets []string, _ ...any) ([]string, error) {
+	if len(targets) == 0 {
+		return nil, protocols.ErrNoTargets
+	}
+
+	first := targets[0]
+	if err := AsBatchUnit(units[first], DefaultBatchConcurrency).SaveMany(ctx, queries, items); err != nil {
+		return nil, fmt.Errorf("error saving unit %w", protocols.UnitErrors{first: err})
+	}
+
+	if len(targets) > 1 {
+		writes := make([]protocols.PendingWrite[K, V], len(queries))
+		for c := range queries {
+			writes[c] = protocols.PendingWrite[K, V]{Query: queries[c], Item: items[c], Targets: append([]string(nil), targets[1:]...)}
+		}
+		if err := w.enqueue(ctx, writes...); err != nil {
+			return []string{first}, err
+		}
+	}
+	return []string{first}, nil
+}
+
+func (w *WriteBehindSaveStrategy[K, V]) enqueue(ctx context.Context, writes ...protocols.PendingWrite[K, V]) error {
+	if err := w.queue.Push(ctx, writes...); err != nil {
+		return fmt.Errorf("error queueing write: %w", err)
+	}
+	select {
+	case w.notify <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// OnDeferredWrite sets the function called with the keys of every flushed
+// batch. The orchestrator calls it when the strategy is registered.
+func (w *WriteBehindSaveStrategy[K, V]) OnDeferredWrite(written func(queries ...K)) {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+	w.written = written
+}
+
+// Backlog returns the number of writes waiting to be flushed.
+func (w *WriteBehindSaveStrategy[K, V]) Backlog() int {
+	return w.queue.Len()
+}
+
+// Flush writes every pending write out before returning, or returns the error
+// of ctx when it is done first.
+func (w *WriteBehindSaveStrategy[K, V]) Flush(ctx context.Context) error {
+	for {
+		flushed, err := w.flushBatch(ctx)
+		if err != nil || flushed == 0 {
+			return err
+		}
+	}
+}
+
+// Close flushes the pending writes within ctx and stops flushing in the
+// background. Writes left in a durable queue are flushed by the next strategy
+// opened on it.
+func (w *WriteBehindSaveStrategy[K, V]) Close(ctx context.Context) error {
+	err := w.Flush(ctx)
+	w.closed.Do(func() {
+		close(w.stop)
+		w.cancel()
+	})
+	<-w.done
+	return err
+}
+
+func (w *WriteBehindSaveStrategy[K, V]) run(ctx context.Context) {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.config.FlushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-w.stop:
+			return
+		case <-w.notify:
+		case <-ticker.C:
+		}
+		w.Flush(ctx)
+	}
+}
+
+// flushBatch writes out the batch at the head of the queue and removes it. It
+// returns how many writes it flushed; writes are only kept in the queue when
+// ctx is done or the units cannot be listed.
+func (w *WriteBehindSaveStrategy[K, V]) flushBatch(ctx context.Context) (int, error) {
+	w.flushMu.Lock()
+	defer w.flushMu.Unlock()
+
+	batch, err := w.queue.Peek(ctx, w.config.BatchSize)
+	if err != nil || len(batch) == 0 {
+		return 0, err
+	}
+
+	units, err := w.units()
+	if err != nil {
+		return 0, fmt.Errorf("error listing units: %w", err)
+	}
+
+	byTarget := map[string][]int{}
+	for index, write := range batch {
+		for _, target := range write.Targets {
+			byTarget[target] = append(byTarget[target], index)
+		}
+	}
+	targets := make([]string, 0, len(byTarget))
+	for target := range byTarget {
+		targets = append(targets, target)
+	}
+	sort.Strings(targets)
+
+	// Some targets may hold the writes even when the flush stopped early.
+	defer w.notifyWritten(batch)
+	for _, target := range targets {
+		if err := w.flushTarget(ctx, units, target, batch, byTarget[target]); err != nil {
+			return 0, err
+		}
+	}
+
+	return len(batch), w.queue.Ack(ctx, len(batch))
+}
+
+// flushTarget saves the writes at indexes of batch to target, retrying the
+// ones that failed. It only returns an error when ctx is done.
+func (w *WriteBehindSaveStrategy[K, V]) flushTarget(ctx context.Context, units map[string]protocols.StorageUnit[K, V], target string, batch []protocols.PendingWrite[K, V], indexes []int) error {
+	unit, ok := units[target]
+	if !ok {
+		w.giveUp(batch, indexes, target, fmt.Errorf("%w: %v", protocols.ErrUnitNotFound, target))
+		return nil
+	}
+
+	// A concurrency of one keeps the writes of a key in order.
+	batchUnit := AsBatchUnit(unit, 1)
+	pending := indexes
+	failures := map[int]error{}
+
+	err := decorators.Retry(ctx, w.config.Retry, func() error {
+		queries := make([]K, len(pending))
+		items := make([]V, len(pending))
+		for position, index := range pending {
+			queries[position], items[position] = batch[index].Query, batch[index].Item
+		}
+
+		err := batchUnit.SaveMany(ctx, queries, items)
+		if err == nil {
+			pending = nil
+			return nil
+		}
+
+		batchErrs, aligned := err.(protocols.BatchErrors)
+		var retry []int
+		for position, index := range pending {
+			switch {
+			case !aligned || len(batchErrs) != len(pending):
+				failures[index] = err
+			case batchErrs[position] != nil:
+				failures[index] = batchErrs[position]
+			default:
+				delete(failures, index)
+				continue
+			}
+			retry = append(retry, index)
+		}
+		pending = retry
+		return err
+	})
+
+	if err != nil && ctx.Err() != nil {
+		return ctx.Err()
+	}
+	for _, index := range pending {
+		w.giveUp(batch, []int{index}, target, failures[index])
+	}
+	return nil
+}
+
+// notifyWritten hands the keys of batch to the function set by
+// OnDeferredWrite. The caller must hold w.flushMu.
+func (w *WriteBehindSaveStrategy[K, V]) notifyWritten(batch []protocols.PendingWrite[K, V]) {
+	if w.written == nil {
+		return
+	}
+	queries := make([]K, len(batch))
+	for c, write := range batch {
+		queries[c] = write.Query
+	}
+	w.written(queries...)
+}
+
+func (w *WriteBehindSaveStrategy[K, V]) giveUp(batch []protocols.PendingWrite[K, V], indexes []int, target string, err error) {
+	if w.config.OnError == nil {
+		return
+	}
+	for _, index := range indexes {
+		w.config.OnError(batch[index], target, err)
+	}
+}
+
+var _ protocols.BatchSaveStrategy[any, any] = (*WriteBehindSaveStrategy[any, any])(nil)
+var _ protocols.DeferredSaveStrategy[any, any] = (*WriteBehindSaveStrategy[any, any])(nil)
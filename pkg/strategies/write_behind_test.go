@@ -0,0 +1,141 @@
+package strategies
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+	"github.com/joaogabriel01/storage-orchestrator/pkg/queues"
+	unit_test "github.com/joaogabriel01/storage-orchestrator/pkg/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+var writeBehindSaveStrategy *WriteBehindSaveStrategy[string, string]
+
+func writeBehindSaveSetup(config WriteBehindConfig[string, string]) {
+	config.FlushInterval = time.Hour
+	flushed := units
+	writeBehindSaveStrategy = NewWriteBehindSaveStrategy[string, string](queues.NewMemoryQueue[string, string](), func() (map[string]protocols.StorageUnit[string, string], error) {
+		return flushed, nil
+	}, config)
+}
+
+func TestWriteBehindSave(t *testing.T) {
+
+	t.Run("should return once the first target saved and flush the others later", func(t *testing.T) {
+		fast := unit_test.NewMemoryUnit[string, string]()
+		release := make(chan struct{})
+		slow := unit_test.NewUnitMock()
+		slow.On("Save", "query", "value", mock.Anything).Run(func(mock.Arguments) {
+			<-release
+		}).Return(nil)
+		units = map[string]protocols.StorageUnit[string, string]{"fast": fast, "slow": slow}
+		writeBehindSaveSetup(WriteBehindConfig[string, string]{})
+		ctx := context.Background()
+
+		saved, err := writeBehindSaveStrategy.Save(ctx, "query", "value", units, []string{"fast", "slow"})
+		assert.NoError(t, err)
+		assert.Equal(t, []string{"fast"}, saved)
+		value, _ := fast.Get(ctx, "query")
+		assert.Equal(t, "value", value)
+		assert.Equal(t, 1, writeBehindSaveStrategy.Backlog())
+
+		close(release)
+		assert.NoError(t, writeBehindSaveStrategy.Close(ctx))
+		assert.Equal(t, 0, writeBehindSaveStrategy.Backlog())
+		slow.AssertExpectations(t)
+	})
+
+	t.Run("should keep the order of the writes of a key", func(t *testing.T) {
+		fast := unit_test.NewMemoryUnit[string, string]()
+		slow := unit_test.NewMemoryUnit[string, string]()
+		units = map[string]protocols.StorageUnit[string, string]{"fast": fast, "slow": slow}
+		writeBehindSaveSetup(WriteBehindConfig[string, string]{})
+		ctx := context.Background()
+
+		for c := 0; c < 50; c++ {
+			_, err := writeBehindSaveStrategy.Save(ctx, "query", fmt.Sprint(c), units, []string{"fast", "slow"})
+			assert.NoError(t, err)
+		}
+		assert.NoError(t, writeBehindSaveStrategy.Close(ctx))
+
+		value, _ := slow.Get(ctx, "query")
+		assert.Equal(t, "49", value)
+	})
+
+	t.Run("should retry a failed target and report the writes it gave up on", func(t *testing.T) {
+		fast := unit_test.NewMemoryUnit[string, string]()
+		broken := unit_test.NewUnitMock()
+		broken.On("Save", "query", "value", mock.Anything).Return(fmt.Errorf("down"))
+		units = map[string]protocols.StorageUnit[string, string]{"fast": fast, "broken": broken}
+
+		var mu sync.Mutex
+		var failed []string
+		writeBehindSaveSetup(WriteBehindConfig[string, string]{
+			Retry: protocols.RetryPolicy{MaxAttempts: 3},
+			OnError: func(write protocols.PendingWrite[string, string], target string, err error) {
+				mu.Lock()
+				defer mu.Unlock()
+				failed = append(failed, fmt.Sprintf("%v %v %v", write.Query, target, err))
+			},
+		})
+		ctx := context.Background()
+
+		_, err := writeBehindSaveStrategy.Save(ctx, "query", "value", units, []string{"fast", "broken"})
+		assert.NoError(t, err)
+		assert.NoError(t, writeBehindSaveStrategy.Close(ctx))
+
+		broken.AssertNumberOfCalls(t, "Save", 3)
+		assert.Equal(t, []string{"query broken down"}, failed)
+		assert.Equal(t, 0, writeBehindSaveStrategy.Backlog())
+	})
+
+	t.Run("should not queue anything when the first target fails", func(t *testing.T) {
+		broken := unit_test.NewUnitMock()
+		broken.On("Save", "query", "value", mock.Anything).Return(fmt.Errorf("down"))
+		units = map[string]protocols.StorageUnit[string, string]{"broken": broken, "slow": unit_test.NewMemoryUnit[string, string]()}
+		writeBehindSaveSetup(WriteBehindConfig[string, string]{})
+		defer writeBehindSaveStrategy.Close(context.Background())
+
+		saved, err := writeBehindSaveStrategy.Save(context.Background(), "query", "value", units, []string{"broken", "slow"})
+		assert.Empty(t, saved)
+		assert.ErrorContains(t, err, "error saving unit broken: down")
+		assert.Equal(t, 0, writeBehindSaveStrategy.Backlog())
+	})
+
+	t.Run("should flush the writes left in a file queue after a restart", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "writes.log")
+		fast := unit_test.NewMemoryUnit[string, string]()
+		slow := unit_test.NewMemoryUnit[string, string]()
+		units := map[string]protocols.StorageUnit[string, string]{"fast": fast, "slow": slow}
+		ctx := context.Background()
+
+		queue, err := queues.OpenFileQueue[string, string](path)
+		assert.NoError(t, err)
+		strategy := NewWriteBehindSaveStrategy[string, string](queue, func() (map[string]protocols.StorageUnit[string, string], error) {
+			return nil, fmt.Errorf("not ready")
+		}, WriteBehindConfig[string, string]{FlushInterval: time.Hour})
+
+		_, err = strategy.SaveMany(ctx, []string{"a", "b"}, []string{"1", "2"}, units, []string{"fast", "slow"})
+		assert.NoError(t, err)
+		assert.ErrorContains(t, strategy.Close(ctx), "not ready")
+		assert.NoError(t, queue.Close())
+
+		queue, err = queues.OpenFileQueue[string, string](path)
+		assert.NoError(t, err)
+		defer queue.Close()
+		strategy = NewWriteBehindSaveStrategy[string, string](queue, func() (map[string]protocols.StorageUnit[string, string], error) {
+			return units, nil
+		}, WriteBehindConfig[string, string]{FlushInterval: time.Hour})
+		assert.Equal(t, 2, strategy.Backlog())
+
+		assert.NoError(t, strategy.Close(ctx))
+		value, _ := slow.Get(ctx, "b")
+		assert.Equal(t, "2", value)
+	})
+}
@@ -0,0 +1,73 @@
+package unit_test
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+type memoryItem[V any] struct {
+	value    V
+	metadata protocols.Metadata
+}
+
+type MemoryUnit[K comparable, V any] struct {
+	mu    sync.RWMutex
+	items map[K]memoryItem[V]
+}
+
+func NewMemoryUnit[K comparable, V any]() *MemoryUnit[K, V] {
+	return &MemoryUnit[K, V]{items: make(map[K]memoryItem[V])}
+}
+
+func (m *MemoryUnit[K, V]) Save(ctx context.Context, query K, item V) error {
+	return m.SaveWithMetadata(ctx, query, item, protocols.Metadata{WrittenAt: time.Now()})
+}
+
+// SaveWithMetadata saves item as if it had been written with metadata, so
+// tests can store values that are already old or stale.
+func (m *MemoryUnit[K, V]) SaveWithMetadata(ctx context.Context, query K, item V, metadata protocols.Metadata) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.items[query] = memoryItem[V]{value: item, metadata: metadata}
+	return nil
+}
+
+func (m *MemoryUnit[K, V]) Get(ctx context.Context, query K) (V, error) {
+	value, _, err := m.GetWithMetadata(ctx, query)
+	return value, err
+}
+
+func (m *MemoryUnit[K, V]) GetWithMetadata(ctx context.Context, query K) (V, protocols.Metadata, error) {
+	var value V
+	if err := ctx.Err(); err != nil {
+		return value, protocols.Metadata{}, err
+	}
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	item, ok := m.items[query]
+	if !ok {
+		return value, protocols.Metadata{}, protocols.ErrNotFound
+	}
+	return item.value, item.metadata, nil
+}
+
+func (m *MemoryUnit[K, V]) Delete(ctx context.Context, query K) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.items, query)
+	return nil
+}
+
+var _ protocols.MetadataStorageUnit[string, string] = (*MemoryUnit[string, string])(nil)
@@ -0,0 +1,75 @@
+package unit_test
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/joaogabriel01/storage-orchestrator/pkg/protocols"
+)
+
+type stagedWrite[K any, V any] struct {
+	query K
+	item  V
+}
+
+// TransactionalMemoryUnit is a MemoryUnit that stages writes in transactions,
+// the reference implementation of protocols.TransactionalStorageUnit. Staged
+// writes are invisible until committed, and committing can only fail for a
+// transaction that was never prepared. Transactions are not isolated from
+// each other: the last one committed wins.
+type TransactionalMemoryUnit[K comparable, V any] struct {
+	*MemoryUnit[K, V]
+	mu     sync.Mutex
+	staged map[string][]stagedWrite[K, V]
+}
+
+func NewTransactionalMemoryUnit[K comparable, V any]() *TransactionalMemoryUnit[K, V] {
+	return &TransactionalMemoryUnit[K, V]{MemoryUnit: NewMemoryUnit[K, V](), staged: make(map[string][]stagedWrite[K, V])}
+}
+
+func (t *TransactionalMemoryUnit[K, V]) Prepare(ctx context.Context, tx string, query K, item V) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.staged[tx] = append(t.staged[tx], stagedWrite[K, V]{query: query, item: item})
+	return nil
+}
+
+func (t *TransactionalMemoryUnit[K, V]) Commit(ctx context.Context, tx string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	writes, ok := t.staged[tx]
+	if !ok {
+		return fmt.Errorf("unknown transaction %v", tx)
+	}
+	delete(t.staged, tx)
+
+	ctx = context.WithoutCancel(ctx)
+	for _, write := range writes {
+		if err := t.MemoryUnit.Save(ctx, write.query, write.item); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *TransactionalMemoryUnit[K, V]) Abort(ctx context.Context, tx string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.staged, tx)
+	return nil
+}
+
+// Pending returns how many transactions were prepared and neither committed
+// nor aborted.
+func (t *TransactionalMemoryUnit[K, V]) Pending() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.staged)
+}
+
+var _ protocols.TransactionalStorageUnit[string, string] = (*TransactionalMemoryUnit[string, string])(nil)
@@ -31,3 +31,28 @@ func (u *UnitMock) Delete(ctx context.Context, query string) error {
 }
 
 var _ protocols.StorageUnit[string, string] = (*UnitMock)(nil)
+
+type BatchUnitMock struct {
+	UnitMock
+}
+
+func NewBatchUnitMock() *BatchUnitMock {
+	return &BatchUnitMock{}
+}
+
+func (u *BatchUnitMock) SaveMany(ctx context.Context, queries []string, items []string) error {
+	args := u.Called(queries, items, ctx)
+	return args.Error(0)
+}
+
+func (u *BatchUnitMock) GetMany(ctx context.Context, queries []string) ([]string, []error) {
+	args := u.Called(queries, ctx)
+	return args.Get(0).([]string), args.Get(1).([]error)
+}
+
+func (u *BatchUnitMock) DeleteMany(ctx context.Context, queries []string) error {
+	args := u.Called(queries, ctx)
+	return args.Error(0)
+}
+
+var _ protocols.BatchStorageUnit[string, string] = (*BatchUnitMock)(nil)